@@ -0,0 +1,623 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package clientset provides version-agnostic access to the TKE APIs. Most
+// typed clients are pinned to one API version; the auth group is the
+// exception because the controller and the apiserver are allowed to run
+// mismatched versions during a rolling upgrade, so NewStackForConfig
+// returns a versioned.Interface whose Role/Policy clients negotiate the
+// server's served version on first use instead of assuming one.
+package clientset
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+
+	auth "tkestack.io/tke/api/auth"
+	authv1 "tkestack.io/tke/api/auth/v1"
+	authinternalversion "tkestack.io/tke/api/client/clientset/internalversion/typed/auth/internalversion"
+	"tkestack.io/tke/api/client/clientset/versioned"
+	authv1client "tkestack.io/tke/api/client/clientset/versioned/typed/auth/v1"
+)
+
+// authServedVersion identifies which version of the auth.tkestack.io group
+// the target apiserver actually serves.
+type authServedVersion string
+
+const (
+	authGroupName = "auth.tkestack.io"
+
+	authServedVersionV1       authServedVersion = "v1"
+	authServedVersionInternal authServedVersion = "__internal"
+)
+
+// negotiateAuthVersion asks the apiserver's discovery API which version of
+// the auth group it serves, preferring v1 and falling back to the
+// internalversion client (used by in-process/aggregated apiservers that
+// skip REST versioning entirely) when v1 isn't advertised. v1 is the only
+// versioned client recognised here; any other advertised version falls
+// back to internalversion.
+func negotiateAuthVersion(discoveryClient discovery.DiscoveryInterface) (authServedVersion, error) {
+	groups, err := discoveryClient.ServerGroups()
+	if err != nil {
+		return "", fmt.Errorf("failed to discover server groups: %v", err)
+	}
+
+	for _, group := range groups.Groups {
+		if group.Name != authGroupName {
+			continue
+		}
+		for _, version := range group.Versions {
+			if version.Version == "v1" {
+				return authServedVersionV1, nil
+			}
+		}
+	}
+
+	return authServedVersionInternal, nil
+}
+
+// NewStackForConfig returns a versioned.Interface whose Role/Policy clients
+// negotiate the served auth API version on first use instead of assuming
+// v1, so a controller built from it (via e.g. policy.NewController) keeps
+// working whether the apiserver it talks to has rolled forward to v1 yet
+// or not.
+func NewStackForConfig(config *rest.Config) (versioned.Interface, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build discovery client: %v", err)
+	}
+
+	authClient, err := newStackAuthClient(config, discoveryClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stackClientset{authV1: authClient}, nil
+}
+
+// stackClientset adapts a stackAuthClient to versioned.Interface.
+type stackClientset struct {
+	authV1 *stackAuthClient
+}
+
+func (c *stackClientset) AuthV1() authv1client.AuthV1Interface {
+	return c.authV1
+}
+
+// stackAuthClient lazily negotiates the served auth API version once and
+// implements authv1client.AuthV1Interface by dispatching every Roles()/
+// Policies() call to whichever typed client matches, converting at the
+// boundary when the apiserver only serves the internal representation.
+type stackAuthClient struct {
+	config    *rest.Config
+	discovery discovery.DiscoveryInterface
+
+	once         sync.Once
+	negotiateErr error
+	version      authServedVersion
+	v1Client     authv1client.AuthV1Interface
+	internal     *authinternalversion.AuthClient
+
+	// users isn't part of the negotiated surface: UserInterface hasn't
+	// been migrated off v1 yet (see versioned/typed/auth/v1/user.go), so
+	// it's built eagerly against v1 rather than going through resolve().
+	users authv1client.UserInterface
+}
+
+// newStackAuthClient builds a stackAuthClient. Role/Policy version
+// negotiation is deferred to first use so it never itself requires a live
+// apiserver connection.
+func newStackAuthClient(config *rest.Config, discoveryClient discovery.DiscoveryInterface) (*stackAuthClient, error) {
+	usersClient, err := authv1client.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build v1 client for Users: %v", err)
+	}
+
+	return &stackAuthClient{
+		config:    config,
+		discovery: discoveryClient,
+		users:     usersClient.Users(),
+	}, nil
+}
+
+func (s *stackAuthClient) resolve() error {
+	s.once.Do(func() {
+		s.version, s.negotiateErr = negotiateAuthVersion(s.discovery)
+		if s.negotiateErr != nil {
+			return
+		}
+
+		switch s.version {
+		case authServedVersionV1:
+			s.v1Client, s.negotiateErr = authv1client.NewForConfig(s.config)
+		default:
+			s.internal, s.negotiateErr = authinternalversion.NewForConfig(s.config)
+		}
+	})
+	return s.negotiateErr
+}
+
+// RESTClient returns the REST client backing whichever version was
+// negotiated, resolving it first if this is the first call.
+func (s *stackAuthClient) RESTClient() rest.Interface {
+	if err := s.resolve(); err != nil {
+		return nil
+	}
+	if s.version == authServedVersionV1 {
+		return s.v1Client.RESTClient()
+	}
+	return s.internal.RESTClient()
+}
+
+// Roles returns a RoleInterface backed by whichever version the apiserver
+// actually serves.
+func (s *stackAuthClient) Roles() authv1client.RoleInterface {
+	return &stackRoleClient{parent: s}
+}
+
+// Policies returns a PolicyInterface backed by whichever version the
+// apiserver actually serves.
+func (s *stackAuthClient) Policies() authv1client.PolicyInterface {
+	return &stackPolicyClient{parent: s}
+}
+
+// Users always talks v1 directly; see the users field doc comment.
+func (s *stackAuthClient) Users() authv1client.UserInterface {
+	return s.users
+}
+
+// stackRoleClient presents a single authv1client.RoleInterface shape
+// regardless of which version stackAuthClient negotiates. Every call goes
+// through resolved(), but the negotiation behind it runs only once on the
+// parent: the version chosen on first use is kept for the client's
+// lifetime, and a later change in the served version is not picked up.
+type stackRoleClient struct {
+	parent *stackAuthClient
+}
+
+func (s *stackRoleClient) resolved() (authv1client.RoleInterface, error) {
+	if err := s.parent.resolve(); err != nil {
+		return nil, err
+	}
+	if s.parent.version == authServedVersionV1 {
+		return s.parent.v1Client.Roles(), nil
+	}
+	return &internalToV1RoleClient{internal: s.parent.internal.Roles()}, nil
+}
+
+func (s *stackRoleClient) Create(ctx context.Context, role *authv1.Role) (*authv1.Role, error) {
+	client, err := s.resolved()
+	if err != nil {
+		return nil, err
+	}
+	return client.Create(ctx, role)
+}
+
+func (s *stackRoleClient) Update(ctx context.Context, role *authv1.Role) (*authv1.Role, error) {
+	client, err := s.resolved()
+	if err != nil {
+		return nil, err
+	}
+	return client.Update(ctx, role)
+}
+
+func (s *stackRoleClient) UpdateStatus(ctx context.Context, role *authv1.Role) (*authv1.Role, error) {
+	client, err := s.resolved()
+	if err != nil {
+		return nil, err
+	}
+	return client.UpdateStatus(ctx, role)
+}
+
+func (s *stackRoleClient) Delete(ctx context.Context, name string, options *metav1.DeleteOptions) error {
+	client, err := s.resolved()
+	if err != nil {
+		return err
+	}
+	return client.Delete(ctx, name, options)
+}
+
+func (s *stackRoleClient) DeleteCollection(ctx context.Context, options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	client, err := s.resolved()
+	if err != nil {
+		return err
+	}
+	return client.DeleteCollection(ctx, options, listOptions)
+}
+
+func (s *stackRoleClient) Get(ctx context.Context, name string, options metav1.GetOptions) (*authv1.Role, error) {
+	client, err := s.resolved()
+	if err != nil {
+		return nil, err
+	}
+	return client.Get(ctx, name, options)
+}
+
+func (s *stackRoleClient) List(ctx context.Context, opts metav1.ListOptions) (*authv1.RoleList, error) {
+	client, err := s.resolved()
+	if err != nil {
+		return nil, err
+	}
+	return client.List(ctx, opts)
+}
+
+func (s *stackRoleClient) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	client, err := s.resolved()
+	if err != nil {
+		return nil, err
+	}
+	return client.Watch(ctx, opts)
+}
+
+func (s *stackRoleClient) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, subresources ...string) (*authv1.Role, error) {
+	client, err := s.resolved()
+	if err != nil {
+		return nil, err
+	}
+	return client.Patch(ctx, name, pt, data, subresources...)
+}
+
+func (s *stackRoleClient) Apply(ctx context.Context, name string, patch []byte, opts metav1.ApplyOptions) (*authv1.Role, error) {
+	client, err := s.resolved()
+	if err != nil {
+		return nil, err
+	}
+	return client.Apply(ctx, name, patch, opts)
+}
+
+func (s *stackRoleClient) CreateOrUpdate(ctx context.Context, name string, mutate func(role *authv1.Role) error) (*authv1.Role, error) {
+	client, err := s.resolved()
+	if err != nil {
+		return nil, err
+	}
+	return client.CreateOrUpdate(ctx, name, mutate)
+}
+
+// stackPolicyClient is the Policy equivalent of stackRoleClient.
+type stackPolicyClient struct {
+	parent *stackAuthClient
+}
+
+func (s *stackPolicyClient) resolved() (authv1client.PolicyInterface, error) {
+	if err := s.parent.resolve(); err != nil {
+		return nil, err
+	}
+	if s.parent.version == authServedVersionV1 {
+		return s.parent.v1Client.Policies(), nil
+	}
+	return &internalToV1PolicyClient{internal: s.parent.internal.Policies()}, nil
+}
+
+func (s *stackPolicyClient) Create(ctx context.Context, policy *authv1.Policy) (*authv1.Policy, error) {
+	client, err := s.resolved()
+	if err != nil {
+		return nil, err
+	}
+	return client.Create(ctx, policy)
+}
+
+func (s *stackPolicyClient) Update(ctx context.Context, policy *authv1.Policy) (*authv1.Policy, error) {
+	client, err := s.resolved()
+	if err != nil {
+		return nil, err
+	}
+	return client.Update(ctx, policy)
+}
+
+func (s *stackPolicyClient) UpdateStatus(ctx context.Context, policy *authv1.Policy) (*authv1.Policy, error) {
+	client, err := s.resolved()
+	if err != nil {
+		return nil, err
+	}
+	return client.UpdateStatus(ctx, policy)
+}
+
+func (s *stackPolicyClient) Delete(ctx context.Context, name string, options *metav1.DeleteOptions) error {
+	client, err := s.resolved()
+	if err != nil {
+		return err
+	}
+	return client.Delete(ctx, name, options)
+}
+
+func (s *stackPolicyClient) DeleteCollection(ctx context.Context, options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	client, err := s.resolved()
+	if err != nil {
+		return err
+	}
+	return client.DeleteCollection(ctx, options, listOptions)
+}
+
+func (s *stackPolicyClient) Get(ctx context.Context, name string, options metav1.GetOptions) (*authv1.Policy, error) {
+	client, err := s.resolved()
+	if err != nil {
+		return nil, err
+	}
+	return client.Get(ctx, name, options)
+}
+
+func (s *stackPolicyClient) List(ctx context.Context, opts metav1.ListOptions) (*authv1.PolicyList, error) {
+	client, err := s.resolved()
+	if err != nil {
+		return nil, err
+	}
+	return client.List(ctx, opts)
+}
+
+func (s *stackPolicyClient) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	client, err := s.resolved()
+	if err != nil {
+		return nil, err
+	}
+	return client.Watch(ctx, opts)
+}
+
+func (s *stackPolicyClient) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, subresources ...string) (*authv1.Policy, error) {
+	client, err := s.resolved()
+	if err != nil {
+		return nil, err
+	}
+	return client.Patch(ctx, name, pt, data, subresources...)
+}
+
+func (s *stackPolicyClient) Apply(ctx context.Context, name string, patch []byte, opts metav1.ApplyOptions) (*authv1.Policy, error) {
+	client, err := s.resolved()
+	if err != nil {
+		return nil, err
+	}
+	return client.Apply(ctx, name, patch, opts)
+}
+
+func (s *stackPolicyClient) CreateOrUpdate(ctx context.Context, name string, mutate func(policy *authv1.Policy) error) (*authv1.Policy, error) {
+	client, err := s.resolved()
+	if err != nil {
+		return nil, err
+	}
+	return client.CreateOrUpdate(ctx, name, mutate)
+}
+
+// internalToV1RoleClient adapts the internalversion RoleInterface (which
+// operates on auth.Role) to the authv1client.RoleInterface shape (which
+// operates on authv1.Role), so stackRoleClient can present a single v1
+// shape to callers regardless of which version is actually being spoken
+// on the wire.
+type internalToV1RoleClient struct {
+	internal authinternalversion.RoleInterface
+}
+
+func (c *internalToV1RoleClient) Create(ctx context.Context, role *authv1.Role) (*authv1.Role, error) {
+	result, err := c.internal.Create(ctx, convertV1RoleToInternal(role))
+	return convertInternalRoleToV1(result), err
+}
+
+func (c *internalToV1RoleClient) Update(ctx context.Context, role *authv1.Role) (*authv1.Role, error) {
+	result, err := c.internal.Update(ctx, convertV1RoleToInternal(role))
+	return convertInternalRoleToV1(result), err
+}
+
+func (c *internalToV1RoleClient) UpdateStatus(ctx context.Context, role *authv1.Role) (*authv1.Role, error) {
+	result, err := c.internal.UpdateStatus(ctx, convertV1RoleToInternal(role))
+	return convertInternalRoleToV1(result), err
+}
+
+func (c *internalToV1RoleClient) Delete(ctx context.Context, name string, options *metav1.DeleteOptions) error {
+	return c.internal.Delete(ctx, name, options)
+}
+
+func (c *internalToV1RoleClient) DeleteCollection(ctx context.Context, options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	return c.internal.DeleteCollection(ctx, options, listOptions)
+}
+
+func (c *internalToV1RoleClient) Get(ctx context.Context, name string, options metav1.GetOptions) (*authv1.Role, error) {
+	result, err := c.internal.Get(ctx, name, options)
+	return convertInternalRoleToV1(result), err
+}
+
+func (c *internalToV1RoleClient) List(ctx context.Context, opts metav1.ListOptions) (*authv1.RoleList, error) {
+	result, err := c.internal.List(ctx, opts)
+	return convertInternalRoleListToV1(result), err
+}
+
+func (c *internalToV1RoleClient) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	w, err := c.internal.Watch(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return watch.Filter(w, func(in watch.Event) (watch.Event, bool) {
+		if role, ok := in.Object.(*auth.Role); ok {
+			in.Object = convertInternalRoleToV1(role)
+		}
+		return in, true
+	}), nil
+}
+
+func (c *internalToV1RoleClient) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, subresources ...string) (*authv1.Role, error) {
+	result, err := c.internal.Patch(ctx, name, pt, data, subresources...)
+	return convertInternalRoleToV1(result), err
+}
+
+func (c *internalToV1RoleClient) Apply(ctx context.Context, name string, patch []byte, opts metav1.ApplyOptions) (*authv1.Role, error) {
+	result, err := c.internal.Apply(ctx, name, patch, opts)
+	return convertInternalRoleToV1(result), err
+}
+
+func (c *internalToV1RoleClient) CreateOrUpdate(ctx context.Context, name string, mutate func(role *authv1.Role) error) (*authv1.Role, error) {
+	result, err := c.internal.CreateOrUpdate(ctx, name, func(role *auth.Role) error {
+		v1Role := convertInternalRoleToV1(role)
+		if err := mutate(v1Role); err != nil {
+			return err
+		}
+		*role = *convertV1RoleToInternal(v1Role)
+		return nil
+	})
+	return convertInternalRoleToV1(result), err
+}
+
+// internalToV1PolicyClient is the Policy equivalent of internalToV1RoleClient.
+type internalToV1PolicyClient struct {
+	internal authinternalversion.PolicyInterface
+}
+
+func (c *internalToV1PolicyClient) Create(ctx context.Context, policy *authv1.Policy) (*authv1.Policy, error) {
+	result, err := c.internal.Create(ctx, convertV1PolicyToInternal(policy))
+	return convertInternalPolicyToV1(result), err
+}
+
+func (c *internalToV1PolicyClient) Update(ctx context.Context, policy *authv1.Policy) (*authv1.Policy, error) {
+	result, err := c.internal.Update(ctx, convertV1PolicyToInternal(policy))
+	return convertInternalPolicyToV1(result), err
+}
+
+func (c *internalToV1PolicyClient) UpdateStatus(ctx context.Context, policy *authv1.Policy) (*authv1.Policy, error) {
+	result, err := c.internal.UpdateStatus(ctx, convertV1PolicyToInternal(policy))
+	return convertInternalPolicyToV1(result), err
+}
+
+func (c *internalToV1PolicyClient) Delete(ctx context.Context, name string, options *metav1.DeleteOptions) error {
+	return c.internal.Delete(ctx, name, options)
+}
+
+func (c *internalToV1PolicyClient) DeleteCollection(ctx context.Context, options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	return c.internal.DeleteCollection(ctx, options, listOptions)
+}
+
+func (c *internalToV1PolicyClient) Get(ctx context.Context, name string, options metav1.GetOptions) (*authv1.Policy, error) {
+	result, err := c.internal.Get(ctx, name, options)
+	return convertInternalPolicyToV1(result), err
+}
+
+func (c *internalToV1PolicyClient) List(ctx context.Context, opts metav1.ListOptions) (*authv1.PolicyList, error) {
+	result, err := c.internal.List(ctx, opts)
+	return convertInternalPolicyListToV1(result), err
+}
+
+func (c *internalToV1PolicyClient) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	w, err := c.internal.Watch(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return watch.Filter(w, func(in watch.Event) (watch.Event, bool) {
+		if policy, ok := in.Object.(*auth.Policy); ok {
+			in.Object = convertInternalPolicyToV1(policy)
+		}
+		return in, true
+	}), nil
+}
+
+func (c *internalToV1PolicyClient) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, subresources ...string) (*authv1.Policy, error) {
+	result, err := c.internal.Patch(ctx, name, pt, data, subresources...)
+	return convertInternalPolicyToV1(result), err
+}
+
+func (c *internalToV1PolicyClient) Apply(ctx context.Context, name string, patch []byte, opts metav1.ApplyOptions) (*authv1.Policy, error) {
+	result, err := c.internal.Apply(ctx, name, patch, opts)
+	return convertInternalPolicyToV1(result), err
+}
+
+func (c *internalToV1PolicyClient) CreateOrUpdate(ctx context.Context, name string, mutate func(policy *authv1.Policy) error) (*authv1.Policy, error) {
+	result, err := c.internal.CreateOrUpdate(ctx, name, func(policy *auth.Policy) error {
+		v1Policy := convertInternalPolicyToV1(policy)
+		if err := mutate(v1Policy); err != nil {
+			return err
+		}
+		*policy = *convertV1PolicyToInternal(v1Policy)
+		return nil
+	})
+	return convertInternalPolicyToV1(result), err
+}
+
+// The convert* helpers below are deliberately small, hand-written
+// shallow copies rather than a generated conversion package: Role and
+// Policy are simple enough (no nested versioned sub-types) that pulling
+// in conversion-gen for two types isn't worth the generated surface yet.
+// If a third version is added, switch to generated conversions instead of
+// growing this by hand.
+
+func convertInternalRoleToV1(in *auth.Role) *authv1.Role {
+	if in == nil {
+		return nil
+	}
+	return &authv1.Role{
+		ObjectMeta: in.ObjectMeta,
+		Spec:       authv1.RoleSpec(in.Spec),
+		Status:     authv1.RoleStatus(in.Status),
+	}
+}
+
+func convertV1RoleToInternal(in *authv1.Role) *auth.Role {
+	if in == nil {
+		return nil
+	}
+	return &auth.Role{
+		ObjectMeta: in.ObjectMeta,
+		Spec:       auth.RoleSpec(in.Spec),
+		Status:     auth.RoleStatus(in.Status),
+	}
+}
+
+func convertInternalRoleListToV1(in *auth.RoleList) *authv1.RoleList {
+	if in == nil {
+		return nil
+	}
+	out := &authv1.RoleList{ListMeta: in.ListMeta}
+	for i := range in.Items {
+		out.Items = append(out.Items, *convertInternalRoleToV1(&in.Items[i]))
+	}
+	return out
+}
+
+func convertInternalPolicyToV1(in *auth.Policy) *authv1.Policy {
+	if in == nil {
+		return nil
+	}
+	return &authv1.Policy{
+		ObjectMeta: in.ObjectMeta,
+		Spec:       authv1.PolicySpec(in.Spec),
+		Status:     authv1.PolicyStatus(in.Status),
+	}
+}
+
+func convertV1PolicyToInternal(in *authv1.Policy) *auth.Policy {
+	if in == nil {
+		return nil
+	}
+	return &auth.Policy{
+		ObjectMeta: in.ObjectMeta,
+		Spec:       auth.PolicySpec(in.Spec),
+		Status:     auth.PolicyStatus(in.Status),
+	}
+}
+
+func convertInternalPolicyListToV1(in *auth.PolicyList) *authv1.PolicyList {
+	if in == nil {
+		return nil
+	}
+	out := &authv1.PolicyList{ListMeta: in.ListMeta}
+	for i := range in.Items {
+		out.Items = append(out.Items, *convertInternalPolicyToV1(&in.Items[i]))
+	}
+	return out
+}
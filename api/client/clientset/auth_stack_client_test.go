@@ -0,0 +1,141 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package clientset
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+
+	auth "tkestack.io/tke/api/auth"
+)
+
+// fakeDiscoveryClient is a minimal discovery.DiscoveryInterface covering
+// only ServerGroups, which is all negotiateAuthVersion calls.
+type fakeDiscoveryClient struct {
+	discovery.DiscoveryInterface
+	groups *metav1.APIGroupList
+	err    error
+}
+
+func (f *fakeDiscoveryClient) ServerGroups() (*metav1.APIGroupList, error) {
+	return f.groups, f.err
+}
+
+func TestNegotiateAuthVersionPrefersV1(t *testing.T) {
+	fd := &fakeDiscoveryClient{groups: &metav1.APIGroupList{
+		Groups: []metav1.APIGroup{
+			{
+				Name:     authGroupName,
+				Versions: []metav1.GroupVersionForDiscovery{{Version: "v1"}},
+			},
+		},
+	}}
+
+	got, err := negotiateAuthVersion(fd)
+	if err != nil {
+		t.Fatalf("negotiateAuthVersion() error = %v", err)
+	}
+	if got != authServedVersionV1 {
+		t.Fatalf("negotiateAuthVersion() = %q, want %q", got, authServedVersionV1)
+	}
+}
+
+func TestNegotiateAuthVersionFallsBackToInternal(t *testing.T) {
+	fd := &fakeDiscoveryClient{groups: &metav1.APIGroupList{
+		Groups: []metav1.APIGroup{
+			{Name: "other.group", Versions: []metav1.GroupVersionForDiscovery{{Version: "v1"}}},
+		},
+	}}
+
+	got, err := negotiateAuthVersion(fd)
+	if err != nil {
+		t.Fatalf("negotiateAuthVersion() error = %v", err)
+	}
+	if got != authServedVersionInternal {
+		t.Fatalf("negotiateAuthVersion() = %q, want %q", got, authServedVersionInternal)
+	}
+}
+
+func TestConvertRoleRoundTrip(t *testing.T) {
+	internal := &auth.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "role-a"},
+	}
+
+	v1Role := convertInternalRoleToV1(internal)
+	if v1Role.Name != internal.Name {
+		t.Fatalf("convertInternalRoleToV1() Name = %q, want %q", v1Role.Name, internal.Name)
+	}
+
+	roundTripped := convertV1RoleToInternal(v1Role)
+	if !reflect.DeepEqual(internal, roundTripped) {
+		t.Fatalf("round trip changed Role: got %#v, want %#v", roundTripped, internal)
+	}
+}
+
+func TestConvertRoleNilIsNil(t *testing.T) {
+	if got := convertInternalRoleToV1(nil); got != nil {
+		t.Fatalf("convertInternalRoleToV1(nil) = %#v, want nil", got)
+	}
+	if got := convertV1RoleToInternal(nil); got != nil {
+		t.Fatalf("convertV1RoleToInternal(nil) = %#v, want nil", got)
+	}
+}
+
+func TestConvertPolicyRoundTrip(t *testing.T) {
+	internal := &auth.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy-a"},
+	}
+
+	v1Policy := convertInternalPolicyToV1(internal)
+	if v1Policy.Name != internal.Name {
+		t.Fatalf("convertInternalPolicyToV1() Name = %q, want %q", v1Policy.Name, internal.Name)
+	}
+
+	roundTripped := convertV1PolicyToInternal(v1Policy)
+	if !reflect.DeepEqual(internal, roundTripped) {
+		t.Fatalf("round trip changed Policy: got %#v, want %#v", roundTripped, internal)
+	}
+}
+
+func TestConvertInternalRoleListToV1(t *testing.T) {
+	in := &auth.RoleList{
+		Items: []auth.Role{
+			{ObjectMeta: metav1.ObjectMeta{Name: "role-a"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "role-b"}},
+		},
+	}
+
+	got := convertInternalRoleListToV1(in)
+	if len(got.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(got.Items))
+	}
+	if got.Items[0].Name != "role-a" || got.Items[1].Name != "role-b" {
+		t.Fatalf("items not converted in order: %#v", got.Items)
+	}
+}
+
+func TestConvertInternalPolicyListToV1Empty(t *testing.T) {
+	got := convertInternalPolicyListToV1(&auth.PolicyList{})
+	if len(got.Items) != 0 {
+		t.Fatalf("expected no items, got %#v", got.Items)
+	}
+}
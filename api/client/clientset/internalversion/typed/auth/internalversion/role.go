@@ -21,12 +21,16 @@
 package internalversion
 
 import (
+	"context"
+	"fmt"
 	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types "k8s.io/apimachinery/pkg/types"
 	watch "k8s.io/apimachinery/pkg/watch"
 	rest "k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
 	auth "tkestack.io/tke/api/auth"
 	scheme "tkestack.io/tke/api/client/clientset/internalversion/scheme"
 )
@@ -39,15 +43,23 @@ type RolesGetter interface {
 
 // RoleInterface has methods to work with Role resources.
 type RoleInterface interface {
-	Create(*auth.Role) (*auth.Role, error)
-	Update(*auth.Role) (*auth.Role, error)
-	UpdateStatus(*auth.Role) (*auth.Role, error)
-	Delete(name string, options *v1.DeleteOptions) error
-	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
-	Get(name string, options v1.GetOptions) (*auth.Role, error)
-	List(opts v1.ListOptions) (*auth.RoleList, error)
-	Watch(opts v1.ListOptions) (watch.Interface, error)
-	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *auth.Role, err error)
+	Create(ctx context.Context, role *auth.Role) (*auth.Role, error)
+	Update(ctx context.Context, role *auth.Role) (*auth.Role, error)
+	UpdateStatus(ctx context.Context, role *auth.Role) (*auth.Role, error)
+	Delete(ctx context.Context, name string, options *v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(ctx context.Context, name string, options v1.GetOptions) (*auth.Role, error)
+	List(ctx context.Context, opts v1.ListOptions) (*auth.RoleList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, subresources ...string) (result *auth.Role, err error)
+	// Apply issues a server-side apply PATCH (application/apply-patch+yaml).
+	// opts.FieldManager is required; the apiserver rejects apply requests
+	// without one.
+	Apply(ctx context.Context, name string, patch []byte, opts v1.ApplyOptions) (result *auth.Role, err error)
+	// CreateOrUpdate fetches the current role, then creates or updates it
+	// with the given mutation applied, retrying with exponential backoff
+	// if it loses a race on ResourceVersion.
+	CreateOrUpdate(ctx context.Context, name string, mutate func(role *auth.Role) error) (result *auth.Role, err error)
 	RoleExpansion
 }
 
@@ -64,19 +76,20 @@ func newRoles(c *AuthClient) *roles {
 }
 
 // Get takes name of the role, and returns the corresponding role object, and an error if there is any.
-func (c *roles) Get(name string, options v1.GetOptions) (result *auth.Role, err error) {
+func (c *roles) Get(ctx context.Context, name string, options v1.GetOptions) (result *auth.Role, err error) {
 	result = &auth.Role{}
 	err = c.client.Get().
 		Resource("roles").
 		Name(name).
 		VersionedParams(&options, scheme.ParameterCodec).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
 }
 
 // List takes label and field selectors, and returns the list of Roles that match those selectors.
-func (c *roles) List(opts v1.ListOptions) (result *auth.RoleList, err error) {
+func (c *roles) List(ctx context.Context, opts v1.ListOptions) (result *auth.RoleList, err error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -86,13 +99,14 @@ func (c *roles) List(opts v1.ListOptions) (result *auth.RoleList, err error) {
 		Resource("roles").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
 }
 
 // Watch returns a watch.Interface that watches the requested roles.
-func (c *roles) Watch(opts v1.ListOptions) (watch.Interface, error) {
+func (c *roles) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -102,27 +116,30 @@ func (c *roles) Watch(opts v1.ListOptions) (watch.Interface, error) {
 		Resource("roles").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
+		Context(ctx).
 		Watch()
 }
 
 // Create takes the representation of a role and creates it.  Returns the server's representation of the role, and an error, if there is any.
-func (c *roles) Create(role *auth.Role) (result *auth.Role, err error) {
+func (c *roles) Create(ctx context.Context, role *auth.Role) (result *auth.Role, err error) {
 	result = &auth.Role{}
 	err = c.client.Post().
 		Resource("roles").
 		Body(role).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
 }
 
 // Update takes the representation of a role and updates it. Returns the server's representation of the role, and an error, if there is any.
-func (c *roles) Update(role *auth.Role) (result *auth.Role, err error) {
+func (c *roles) Update(ctx context.Context, role *auth.Role) (result *auth.Role, err error) {
 	result = &auth.Role{}
 	err = c.client.Put().
 		Resource("roles").
 		Name(role.Name).
 		Body(role).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
@@ -131,30 +148,32 @@ func (c *roles) Update(role *auth.Role) (result *auth.Role, err error) {
 // UpdateStatus was generated because the type contains a Status member.
 // Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
 
-func (c *roles) UpdateStatus(role *auth.Role) (result *auth.Role, err error) {
+func (c *roles) UpdateStatus(ctx context.Context, role *auth.Role) (result *auth.Role, err error) {
 	result = &auth.Role{}
 	err = c.client.Put().
 		Resource("roles").
 		Name(role.Name).
 		SubResource("status").
 		Body(role).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
 }
 
 // Delete takes name of the role and deletes it. Returns an error if one occurs.
-func (c *roles) Delete(name string, options *v1.DeleteOptions) error {
+func (c *roles) Delete(ctx context.Context, name string, options *v1.DeleteOptions) error {
 	return c.client.Delete().
 		Resource("roles").
 		Name(name).
 		Body(options).
+		Context(ctx).
 		Do().
 		Error()
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *roles) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+func (c *roles) DeleteCollection(ctx context.Context, options *v1.DeleteOptions, listOptions v1.ListOptions) error {
 	var timeout time.Duration
 	if listOptions.TimeoutSeconds != nil {
 		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
@@ -164,19 +183,72 @@ func (c *roles) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListO
 		VersionedParams(&listOptions, scheme.ParameterCodec).
 		Timeout(timeout).
 		Body(options).
+		Context(ctx).
 		Do().
 		Error()
 }
 
 // Patch applies the patch and returns the patched role.
-func (c *roles) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *auth.Role, err error) {
+func (c *roles) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, subresources ...string) (result *auth.Role, err error) {
 	result = &auth.Role{}
 	err = c.client.Patch(pt).
 		Resource("roles").
 		SubResource(subresources...).
 		Name(name).
 		Body(data).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
 }
+
+// Apply issues a server-side apply PATCH for the role, requiring a field
+// manager so ownership of the applied fields can be tracked.
+func (c *roles) Apply(ctx context.Context, name string, patch []byte, opts v1.ApplyOptions) (result *auth.Role, err error) {
+	if opts.FieldManager == "" {
+		return nil, fmt.Errorf("fieldManager is required for Apply")
+	}
+
+	patchOptions := v1.PatchOptions{Force: &opts.Force, FieldManager: opts.FieldManager}
+	result = &auth.Role{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Resource("roles").
+		Name(name).
+		VersionedParams(&patchOptions, scheme.ParameterCodec).
+		Body(patch).
+		Context(ctx).
+		Do().
+		Into(result)
+	return
+}
+
+// CreateOrUpdate retries the read-modify-write cycle on conflict with
+// exponential backoff, so callers can mutate a role without hand-rolling
+// their own retry loop around Get/Update.
+func (c *roles) CreateOrUpdate(ctx context.Context, name string, mutate func(role *auth.Role) error) (result *auth.Role, err error) {
+	retryErr := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		existing, getErr := c.Get(ctx, name, v1.GetOptions{})
+		if apierrors.IsNotFound(getErr) {
+			role := &auth.Role{}
+			role.Name = name
+			if mutateErr := mutate(role); mutateErr != nil {
+				return mutateErr
+			}
+			result, err = c.Create(ctx, role)
+			return err
+		}
+		if getErr != nil {
+			return getErr
+		}
+
+		if mutateErr := mutate(existing); mutateErr != nil {
+			return mutateErr
+		}
+		result, err = c.Update(ctx, existing)
+		return err
+	})
+	if retryErr != nil {
+		return nil, retryErr
+	}
+	return result, nil
+}
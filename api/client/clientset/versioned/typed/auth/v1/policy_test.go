@@ -0,0 +1,36 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestPoliciesApplyRequiresFieldManager is the Policy equivalent of
+// TestRolesApplyRequiresFieldManager; see that test's comment for why the
+// rest of policies isn't covered here.
+func TestPoliciesApplyRequiresFieldManager(t *testing.T) {
+	c := &policies{}
+	if _, err := c.Apply(context.Background(), "policy-a", []byte("{}"), metav1.ApplyOptions{}); err == nil {
+		t.Fatal("Apply() with no FieldManager should have errored")
+	}
+}
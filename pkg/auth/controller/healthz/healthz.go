@@ -0,0 +1,135 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package healthz lets controllers report their informer-synced state and
+// the age of their last successful reconcile, and turns that into
+// /healthz and /readyz HTTP handlers for the auth controller-manager. It
+// is a leaf package so policy/user controllers and the controller-manager
+// package that wires them up can both import it without an import cycle.
+package healthz
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Reporter is implemented by every controller the auth controller-manager
+// runs so Registry can ask it whether it's caught up.
+type Reporter interface {
+	// Healthy reports whether the controller's informer cache has synced
+	// and, if so, how long ago its last successful sync completed.
+	Healthy() (synced bool, lastSync time.Time)
+}
+
+// Registry tracks the Reporters registered for a running controller
+// manager and serves /healthz (process liveness only) and /readyz (based on
+// their combined state).
+type Registry struct {
+	// maxSyncAge is how stale a controller's last successful sync is
+	// allowed to be before readyz reports it unready.
+	maxSyncAge time.Duration
+
+	mu        sync.RWMutex
+	reporters map[string]Reporter
+}
+
+// NewRegistry creates a Registry that considers a controller unready once
+// its last successful sync is older than maxSyncAge.
+func NewRegistry(maxSyncAge time.Duration) *Registry {
+	return &Registry{
+		maxSyncAge: maxSyncAge,
+		reporters:  make(map[string]Reporter),
+	}
+}
+
+// Register adds a controller's Reporter under name, overwriting any
+// previous registration with the same name.
+func (r *Registry) Register(name string, reporter Reporter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reporters[name] = reporter
+}
+
+// status is the health of a single registered controller.
+type status struct {
+	name      string
+	synced    bool
+	lastSync  time.Time
+	syncStale bool
+}
+
+func (r *Registry) collect() []status {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	statuses := make([]status, 0, len(r.reporters))
+	for name, reporter := range r.reporters {
+		synced, lastSync := reporter.Healthy()
+		statuses = append(statuses, status{
+			name:      name,
+			synced:    synced,
+			lastSync:  lastSync,
+			syncStale: synced && !lastSync.IsZero() && time.Since(lastSync) > r.maxSyncAge,
+		})
+	}
+	return statuses
+}
+
+// HealthzHandler always reports ok as long as the process is alive and
+// serving; it doesn't depend on informers having synced.
+func (r *Registry) HealthzHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	})
+}
+
+// ReadyzHandler reports ready only once every registered controller's
+// informer cache has synced and its last successful sync isn't older than
+// the registry's maxSyncAge.
+func (r *Registry) ReadyzHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		statuses := r.collect()
+
+		ready := true
+		for _, s := range statuses {
+			if !s.synced || s.syncStale {
+				ready = false
+			}
+		}
+
+		if !ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+
+		for _, s := range statuses {
+			switch {
+			case !s.synced:
+				fmt.Fprintf(w, "%s: not synced\n", s.name)
+			case s.syncStale:
+				fmt.Fprintf(w, "%s: last sync %s ago (stale)\n", s.name, time.Since(s.lastSync))
+			default:
+				fmt.Fprintf(w, "%s: ok\n", s.name)
+			}
+		}
+	})
+}
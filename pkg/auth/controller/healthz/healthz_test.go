@@ -0,0 +1,105 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package healthz
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fakeReporter struct {
+	synced   bool
+	lastSync time.Time
+}
+
+func (f fakeReporter) Healthy() (bool, time.Time) { return f.synced, f.lastSync }
+
+func TestHealthzHandlerAlwaysOK(t *testing.T) {
+	r := NewRegistry(time.Minute)
+	rec := httptest.NewRecorder()
+	r.HealthzHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("HealthzHandler status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestReadyzHandlerNotReadyUntilSynced(t *testing.T) {
+	r := NewRegistry(time.Minute)
+	r.Register("policy-controller", fakeReporter{synced: false})
+
+	rec := httptest.NewRecorder()
+	r.ReadyzHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("ReadyzHandler status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestReadyzHandlerReadyWhenSyncedAndFresh(t *testing.T) {
+	r := NewRegistry(time.Minute)
+	r.Register("policy-controller", fakeReporter{synced: true, lastSync: time.Now()})
+
+	rec := httptest.NewRecorder()
+	r.ReadyzHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("ReadyzHandler status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestReadyzHandlerUnreadyWhenSyncStale(t *testing.T) {
+	r := NewRegistry(time.Minute)
+	r.Register("policy-controller", fakeReporter{synced: true, lastSync: time.Now().Add(-2 * time.Minute)})
+
+	rec := httptest.NewRecorder()
+	r.ReadyzHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("ReadyzHandler status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestReadyzHandlerIgnoresStalenessBeforeFirstSync(t *testing.T) {
+	r := NewRegistry(time.Minute)
+	r.Register("policy-controller", fakeReporter{synced: true})
+
+	rec := httptest.NewRecorder()
+	r.ReadyzHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("ReadyzHandler status = %d, want %d (zero lastSync shouldn't count as stale)", rec.Code, http.StatusOK)
+	}
+}
+
+func TestRegisterOverwritesExistingReporter(t *testing.T) {
+	r := NewRegistry(time.Minute)
+	r.Register("policy-controller", fakeReporter{synced: false})
+	r.Register("policy-controller", fakeReporter{synced: true, lastSync: time.Now()})
+
+	statuses := r.collect()
+	if len(statuses) != 1 {
+		t.Fatalf("got %d statuses, want 1", len(statuses))
+	}
+	if !statuses[0].synced {
+		t.Fatalf("expected the second registration to win, got synced=%v", statuses[0].synced)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package manager wires up the controllers owned by the auth
+// component (policy, user, ...) behind a single InitFunc map so the
+// tke-auth-controller-manager binary can start them uniformly.
+package manager
+
+import (
+	"time"
+
+	clientset "tkestack.io/tke/api/client/clientset/versioned"
+	"tkestack.io/tke/api/client/informers/externalversions"
+	"tkestack.io/tke/pkg/auth/controller/healthz"
+	"tkestack.io/tke/pkg/auth/controller/policy"
+	"tkestack.io/tke/pkg/auth/controller/user"
+	"tkestack.io/tke/pkg/util/log"
+)
+
+// ControllerContext holds everything an InitFunc needs to build and run a
+// controller: the client the controller talks to the apiserver with, the
+// shared informer factory its listers/informers come from, the resync
+// period informers should use, and the channel that signals shutdown.
+type ControllerContext struct {
+	Client               clientset.Interface
+	InformerFactory      externalversions.SharedInformerFactory
+	ResyncPeriod         time.Duration
+	AuthorizationOptions *policy.AuthorizationOptions
+	Workers              int
+	Stop                 <-chan struct{}
+
+	// HealthRegistry receives the started controller so /healthz and
+	// /readyz can report its informer-synced state and last sync age. It
+	// may be nil, in which case controllers simply aren't monitored.
+	HealthRegistry *healthz.Registry
+}
+
+// InitFunc starts a controller given the shared context, returning
+// whether it was actually started (some controllers may be disabled by
+// configuration) and any error encountered while building it.
+type InitFunc func(ctx ControllerContext) (started bool, err error)
+
+// AddControllers returns the InitFunc for every controller the auth
+// controller-manager knows how to run, keyed by controller name so
+// callers can enable/disable individual controllers by name.
+func AddControllers() map[string]InitFunc {
+	controllers := map[string]InitFunc{}
+	controllers["policy-controller"] = startPolicyController
+	controllers["user-controller"] = startUserController
+	return controllers
+}
+
+func startPolicyController(ctx ControllerContext) (bool, error) {
+	controller, err := policy.NewController(
+		ctx.Client,
+		ctx.InformerFactory.Auth().V1().Policies(),
+		ctx.ResyncPeriod,
+		ctx.AuthorizationOptions,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if ctx.HealthRegistry != nil {
+		ctx.HealthRegistry.Register("policy-controller", controller)
+	}
+
+	go controller.Run(ctx.Workers, ctx.Stop)
+	return true, nil
+}
+
+func startUserController(ctx ControllerContext) (bool, error) {
+	controller := user.NewController(
+		ctx.Client,
+		ctx.InformerFactory.Auth().V1().Users(),
+		ctx.ResyncPeriod,
+	)
+
+	if ctx.HealthRegistry != nil {
+		ctx.HealthRegistry.Register("user-controller", controller)
+	}
+
+	go controller.Run(ctx.Workers, ctx.Stop)
+	return true, nil
+}
+
+// LogStart logs which controllers actually started, so a misconfigured
+// deployment is easy to spot in the auth controller-manager's logs.
+func LogStart(started map[string]bool) {
+	for name, ok := range started {
+		if ok {
+			log.Infof("Started %q controller", name)
+		} else {
+			log.Infof("Skipping %q controller", name)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package manager
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"tkestack.io/tke/pkg/auth/controller/healthz"
+	"tkestack.io/tke/pkg/util/log"
+)
+
+// ServeHealthz starts an HTTP server on opts.HealthzBindAddress exposing
+// /healthz and /readyz for registry, and shuts it down once ctx is
+// cancelled. It returns once the listener is up; errors encountered while
+// serving are logged rather than returned, matching how the rest of this
+// package treats background goroutines.
+func ServeHealthz(ctx context.Context, opts *ControllerManagerOptions, registry *healthz.Registry) error {
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", registry.HealthzHandler())
+	mux.Handle("/readyz", registry.ReadyzHandler())
+
+	server := &http.Server{
+		Addr:    opts.HealthzBindAddress,
+		Handler: mux,
+	}
+
+	ln, err := net.Listen("tcp", opts.HealthzBindAddress)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	go func() {
+		log.Infof("Serving /healthz and /readyz on %s", opts.HealthzBindAddress)
+		if err := server.Serve(ln); err != nil && err != http.ErrServerClosed {
+			log.Error("Health server exited with error", log.Err(err))
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"tkestack.io/tke/pkg/util/log"
+)
+
+// RunWithLeaderElection calls run once this process has acquired the
+// leader election Lease configured by opts, and blocks until leadership is
+// lost or ctx is cancelled. If opts.LeaderElect is false, run is called
+// immediately without any election taking place, which is handy for
+// single-replica deployments and local development.
+func RunWithLeaderElection(ctx context.Context, kubeClient kubernetes.Interface, opts *ControllerManagerOptions, run func(ctx context.Context)) error {
+	if !opts.LeaderElect {
+		run(ctx)
+		return nil
+	}
+
+	identity, err := newIdentity()
+	if err != nil {
+		return fmt.Errorf("failed to determine leader election identity: %v", err)
+	}
+
+	lock, err := resourcelock.New(
+		resourcelock.LeasesResourceLock,
+		opts.LeaderElectionNamespace,
+		opts.LeaderElectionName,
+		kubeClient.CoreV1(),
+		kubeClient.CoordinationV1(),
+		resourcelock.ResourceLockConfig{
+			Identity: identity,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create leader election resource lock: %v", err)
+	}
+
+	leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: opts.LeaseDuration,
+		RenewDeadline: opts.RenewDeadline,
+		RetryPeriod:   opts.RetryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				log.Info("Acquired leader election, starting controllers")
+				run(ctx)
+			},
+			OnStoppedLeading: func() {
+				log.Infof("Leader election lost for identity %q, shutting down", identity)
+			},
+			OnNewLeader: func(identity string) {
+				log.Infof("New leader elected: %s", identity)
+			},
+		},
+	})
+
+	return nil
+}
+
+// newIdentity builds a leader election identity from the process hostname
+// plus a random suffix, so multiple replicas on the same host (e.g. in
+// local development) don't collide.
+func newIdentity() (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", err
+	}
+	return hostname + "_" + string(uuid.NewUUID()), nil
+}
@@ -0,0 +1,124 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package manager
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// ControllerManagerOptions holds the configuration needed to run the auth
+// controller-manager itself: leader election and the health server, as
+// opposed to AuthorizationOptions, which configures the policy controller
+// it starts.
+type ControllerManagerOptions struct {
+	// LeaderElect enables leader election so only one replica of the auth
+	// controller-manager processes controllers at a time.
+	LeaderElect bool
+	// LeaderElectionNamespace is the namespace the leader election Lease
+	// object is created in.
+	LeaderElectionNamespace string
+	// LeaderElectionName is the name of the Lease object used to coordinate
+	// leader election.
+	LeaderElectionName string
+	// LeaseDuration is the duration non-leader replicas wait before trying
+	// to acquire leadership.
+	LeaseDuration time.Duration
+	// RenewDeadline is how long the current leader tries to renew its
+	// lease before giving it up.
+	RenewDeadline time.Duration
+	// RetryPeriod is how long leader election clients wait between tries
+	// of actions.
+	RetryPeriod time.Duration
+
+	// HealthzBindAddress is the address the /healthz and /readyz endpoints
+	// are served on.
+	HealthzBindAddress string
+	// MaxSyncAge is how stale a controller's last successful sync can be
+	// before /readyz reports it unready.
+	MaxSyncAge time.Duration
+}
+
+// NewControllerManagerOptions returns options defaulted to the same leader
+// election timings the upstream kube-controller-manager uses.
+func NewControllerManagerOptions() *ControllerManagerOptions {
+	return &ControllerManagerOptions{
+		LeaderElect:             true,
+		LeaderElectionNamespace: "kube-system",
+		LeaderElectionName:      "tke-auth-controller-manager",
+		LeaseDuration:           15 * time.Second,
+		RenewDeadline:           10 * time.Second,
+		RetryPeriod:             2 * time.Second,
+		HealthzBindAddress:      "0.0.0.0:10257",
+		MaxSyncAge:              5 * time.Minute,
+	}
+}
+
+// AddFlags adds the controller-manager flags to the specified FlagSet.
+func (o *ControllerManagerOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.LeaderElect, "leader-elect", o.LeaderElect,
+		"Whether to use leader election so only one replica of the auth controller-manager is active.")
+	fs.StringVar(&o.LeaderElectionNamespace, "leader-elect-resource-namespace", o.LeaderElectionNamespace,
+		"The namespace the leader election Lease object is created in.")
+	fs.StringVar(&o.LeaderElectionName, "leader-elect-resource-name", o.LeaderElectionName,
+		"The name of the Lease object used to coordinate leader election.")
+	fs.DurationVar(&o.LeaseDuration, "leader-elect-lease-duration", o.LeaseDuration,
+		"The duration non-leader replicas wait before trying to acquire leadership.")
+	fs.DurationVar(&o.RenewDeadline, "leader-elect-renew-deadline", o.RenewDeadline,
+		"The duration the current leader tries to renew its lease before giving it up.")
+	fs.DurationVar(&o.RetryPeriod, "leader-elect-retry-period", o.RetryPeriod,
+		"The duration leader election clients wait between tries of actions.")
+	fs.StringVar(&o.HealthzBindAddress, "healthz-bind-address", o.HealthzBindAddress,
+		"The address the /healthz and /readyz endpoints are served on.")
+	fs.DurationVar(&o.MaxSyncAge, "max-sync-age", o.MaxSyncAge,
+		"How stale a controller's last successful sync can be before /readyz reports it unready.")
+}
+
+// Validate checks the options are internally consistent, returning all
+// errors found rather than failing on the first one.
+func (o *ControllerManagerOptions) Validate() []error {
+	var errs []error
+
+	if o.LeaderElect {
+		if o.LeaderElectionNamespace == "" {
+			errs = append(errs, fmt.Errorf("leader-elect-resource-namespace must be set when leader-elect is true"))
+		}
+		if o.LeaderElectionName == "" {
+			errs = append(errs, fmt.Errorf("leader-elect-resource-name must be set when leader-elect is true"))
+		}
+		if o.RenewDeadline >= o.LeaseDuration {
+			errs = append(errs, fmt.Errorf("leader-elect-renew-deadline must be less than leader-elect-lease-duration"))
+		}
+		if o.RetryPeriod >= o.RenewDeadline {
+			errs = append(errs, fmt.Errorf("leader-elect-retry-period must be less than leader-elect-renew-deadline"))
+		}
+	}
+
+	if o.HealthzBindAddress == "" {
+		errs = append(errs, fmt.Errorf("healthz-bind-address must be set"))
+	}
+
+	if o.MaxSyncAge <= 0 {
+		errs = append(errs, fmt.Errorf("max-sync-age must be greater than zero"))
+	}
+
+	return errs
+}
@@ -0,0 +1,88 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package manager
+
+import "testing"
+
+func TestNewControllerManagerOptionsIsValid(t *testing.T) {
+	opts := NewControllerManagerOptions()
+	if errs := opts.Validate(); len(errs) != 0 {
+		t.Fatalf("default options should be valid, got errs %v", errs)
+	}
+}
+
+func TestControllerManagerOptionsValidate(t *testing.T) {
+	valid := func() *ControllerManagerOptions {
+		opts := NewControllerManagerOptions()
+		return opts
+	}
+
+	cases := []struct {
+		name    string
+		mutate  func(*ControllerManagerOptions)
+		wantErr bool
+	}{
+		{
+			name:    "leader election disabled skips its checks",
+			mutate:  func(o *ControllerManagerOptions) { o.LeaderElect = false; o.LeaderElectionNamespace = "" },
+			wantErr: false,
+		},
+		{
+			name:    "leader election requires a namespace",
+			mutate:  func(o *ControllerManagerOptions) { o.LeaderElectionNamespace = "" },
+			wantErr: true,
+		},
+		{
+			name:    "leader election requires a lease name",
+			mutate:  func(o *ControllerManagerOptions) { o.LeaderElectionName = "" },
+			wantErr: true,
+		},
+		{
+			name:    "renew deadline must be less than lease duration",
+			mutate:  func(o *ControllerManagerOptions) { o.RenewDeadline = o.LeaseDuration },
+			wantErr: true,
+		},
+		{
+			name:    "retry period must be less than renew deadline",
+			mutate:  func(o *ControllerManagerOptions) { o.RetryPeriod = o.RenewDeadline },
+			wantErr: true,
+		},
+		{
+			name:    "healthz bind address is required",
+			mutate:  func(o *ControllerManagerOptions) { o.HealthzBindAddress = "" },
+			wantErr: true,
+		},
+		{
+			name:    "max sync age must be positive",
+			mutate:  func(o *ControllerManagerOptions) { o.MaxSyncAge = 0 },
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := valid()
+			tc.mutate(opts)
+			errs := opts.Validate()
+			if (len(errs) > 0) != tc.wantErr {
+				t.Fatalf("Validate() errs = %v, wantErr %v", errs, tc.wantErr)
+			}
+		})
+	}
+}
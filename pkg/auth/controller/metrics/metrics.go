@@ -0,0 +1,99 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package metrics holds the Prometheus instrumentation shared by every
+// controller the auth controller-manager runs. It is a leaf package (no
+// dependency on the controllers themselves) so policy/user controllers and
+// the controller-manager package that starts them can both import it
+// without creating an import cycle.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const subsystem = "auth_controller_manager"
+
+var (
+	workqueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: subsystem,
+		Name:      "workqueue_depth",
+		Help:      "Current depth of a controller's workqueue.",
+	}, []string{"controller"})
+
+	syncDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: subsystem,
+		Name:      "sync_duration_seconds",
+		Help:      "Time it took to sync a single item, in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"controller"})
+
+	syncErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: subsystem,
+		Name:      "sync_errors_total",
+		Help:      "Total number of syncItem calls that returned an error.",
+	}, []string{"controller"})
+
+	reconcilesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: subsystem,
+		Name:      "reconciles_total",
+		Help:      "Total number of reconciles, partitioned by result.",
+	}, []string{"controller", "result"})
+)
+
+func init() {
+	prometheus.MustRegister(workqueueDepth, syncDurationSeconds, syncErrorsTotal, reconcilesTotal)
+}
+
+// Result is the outcome of a single syncItem call, used as the
+// reconciles_total "result" label.
+type Result string
+
+const (
+	// ResultSuccess means the item was synced with no error and does not
+	// need to be requeued.
+	ResultSuccess Result = "success"
+	// ResultRequeue means the item was synced with no error but asked to
+	// be requeued (e.g. a resource isn't ready yet).
+	ResultRequeue Result = "requeue"
+	// ResultError means syncItem returned an error.
+	ResultError Result = "error"
+)
+
+// ObserveSync records how long a syncItem call for controller took and
+// classifies its outcome into reconciles_total/sync_errors_total.
+func ObserveSync(controller string, start time.Time, requeue bool, err error) {
+	syncDurationSeconds.WithLabelValues(controller).Observe(time.Since(start).Seconds())
+
+	result := ResultSuccess
+	switch {
+	case err != nil:
+		result = ResultError
+		syncErrorsTotal.WithLabelValues(controller).Inc()
+	case requeue:
+		result = ResultRequeue
+	}
+	reconcilesTotal.WithLabelValues(controller, string(result)).Inc()
+}
+
+// SetQueueDepth records the current depth of controller's workqueue.
+func SetQueueDepth(controller string, depth int) {
+	workqueueDepth.WithLabelValues(controller).Set(float64(depth))
+}
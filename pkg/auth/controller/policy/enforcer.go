@@ -0,0 +1,70 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package policy
+
+import (
+	"fmt"
+
+	v1 "tkestack.io/tke/api/auth/v1"
+)
+
+// PolicyEnforcer translates a v1.Policy's statements into rules understood
+// by a concrete authorization backend and keeps that backend in sync with
+// the cluster's Policy objects. Implementations must be safe for concurrent
+// use by multiple workers.
+type PolicyEnforcer interface {
+	// LoadPolicy translates policy.Spec.Statement into backend rules and
+	// (re)loads them, replacing any rules previously loaded for the same
+	// policy name. It returns a digest that is stable across calls as
+	// long as the effective rule set does not change, so callers can use
+	// it to detect whether Status needs to be updated.
+	LoadPolicy(policy *v1.Policy) (digest string, err error)
+	// RemovePolicy removes every rule previously loaded for the named
+	// policy. It is a no-op if the policy was never loaded.
+	RemovePolicy(name string) error
+}
+
+// authorizationMode identifies a PolicyEnforcer implementation.
+type authorizationMode string
+
+const (
+	// AuthorizationModeCasbin enforces policies with an embedded casbin
+	// enforcer backed by an RBAC model.
+	AuthorizationModeCasbin authorizationMode = "casbin"
+	// AuthorizationModeRBAC translates policies into native Kubernetes
+	// RBAC ClusterRole objects; no binding to the statement subjects is
+	// created.
+	AuthorizationModeRBAC authorizationMode = "rbac"
+)
+
+// newPolicyEnforcer builds the PolicyEnforcer selected by opts.Mode.
+func newPolicyEnforcer(opts *AuthorizationOptions) (PolicyEnforcer, error) {
+	if opts == nil {
+		opts = NewAuthorizationOptions()
+	}
+
+	switch authorizationMode(opts.Mode) {
+	case AuthorizationModeCasbin:
+		return newCasbinEnforcer(opts.CasbinModelFile)
+	case AuthorizationModeRBAC:
+		return newRBACEnforcer(opts.RESTConfig)
+	default:
+		return nil, fmt.Errorf("unsupported authorization mode %q", opts.Mode)
+	}
+}
@@ -0,0 +1,113 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package policy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+
+	v1 "tkestack.io/tke/api/auth/v1"
+)
+
+// casbinEnforcer loads Policy statements into a single in-process casbin
+// enforcer, namespacing every rule by policy name so RemovePolicy can undo
+// exactly what LoadPolicy added.
+type casbinEnforcer struct {
+	mu       sync.Mutex
+	enforcer *casbin.Enforcer
+}
+
+func newCasbinEnforcer(modelFile string) (PolicyEnforcer, error) {
+	m, err := model.NewModelFromFile(modelFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load casbin model %s: %v", modelFile, err)
+	}
+
+	enforcer, err := casbin.NewEnforcer(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create casbin enforcer: %v", err)
+	}
+
+	return &casbinEnforcer{enforcer: enforcer}, nil
+}
+
+func (c *casbinEnforcer) LoadPolicy(policy *v1.Policy) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.enforcer.RemoveFilteredPolicy(0, policy.Name); err != nil {
+		return "", fmt.Errorf("failed to clear existing rules for policy %s: %v", policy.Name, err)
+	}
+
+	rules := statementsToCasbinRules(policy.Name, policy.Spec.Statement)
+	if len(rules) > 0 {
+		if _, err := c.enforcer.AddPolicies(rules); err != nil {
+			return "", fmt.Errorf("failed to load rules for policy %s: %v", policy.Name, err)
+		}
+	}
+
+	return digestRules(rules), nil
+}
+
+func (c *casbinEnforcer) RemovePolicy(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.enforcer.RemoveFilteredPolicy(0, name)
+	return err
+}
+
+// statementsToCasbinRules flattens a policy's statements into casbin
+// ptype rows of the form (policyName, subject, resource, action, effect).
+func statementsToCasbinRules(policyName string, statements []v1.Statement) [][]string {
+	var rules [][]string
+	for _, statement := range statements {
+		for _, subject := range statement.Subjects {
+			for _, resource := range statement.Resources {
+				for _, action := range statement.Actions {
+					rules = append(rules, []string{policyName, subject, resource, action, string(statement.Effect)})
+				}
+			}
+		}
+	}
+	return rules
+}
+
+// digestRules returns a stable content hash so the controller can tell
+// whether the effective rule set changed without diffing it field by
+// field on every sync.
+func digestRules(rules [][]string) string {
+	flat := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		flat = append(flat, fmt.Sprintf("%v", rule))
+	}
+	sort.Strings(flat)
+
+	h := sha256.New()
+	for _, line := range flat {
+		_, _ = h.Write([]byte(line))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,74 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package policy
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "tkestack.io/tke/api/auth/v1"
+)
+
+func TestStatementsToCasbinRules(t *testing.T) {
+	statements := []v1.Statement{
+		{
+			Subjects:  []string{"alice", "bob"},
+			Resources: []string{"pods"},
+			Actions:   []string{"get", "list"},
+			Effect:    "allow",
+		},
+	}
+
+	got := statementsToCasbinRules("policy-a", statements)
+
+	want := [][]string{
+		{"policy-a", "alice", "pods", "get", "allow"},
+		{"policy-a", "alice", "pods", "list", "allow"},
+		{"policy-a", "bob", "pods", "get", "allow"},
+		{"policy-a", "bob", "pods", "list", "allow"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("statementsToCasbinRules() = %#v, want %#v", got, want)
+	}
+}
+
+func TestStatementsToCasbinRulesEmpty(t *testing.T) {
+	if got := statementsToCasbinRules("policy-a", nil); got != nil {
+		t.Fatalf("statementsToCasbinRules(nil) = %#v, want nil", got)
+	}
+}
+
+func TestDigestRulesStableAcrossOrder(t *testing.T) {
+	a := [][]string{{"x"}, {"y"}}
+	b := [][]string{{"y"}, {"x"}}
+
+	if digestRules(a) != digestRules(b) {
+		t.Fatalf("digestRules should not depend on rule order")
+	}
+}
+
+func TestDigestRulesChangesWithContent(t *testing.T) {
+	a := [][]string{{"policy-a", "alice", "pods", "get", "allow"}}
+	b := [][]string{{"policy-a", "alice", "pods", "delete", "allow"}}
+
+	if digestRules(a) == digestRules(b) {
+		t.Fatalf("digestRules should change when rule content changes")
+	}
+}
@@ -0,0 +1,115 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package policy
+
+import (
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	v1 "tkestack.io/tke/api/auth/v1"
+)
+
+// rbacEnforcer translates Policy statements into native Kubernetes
+// ClusterRole objects, one per policy name. It creates no ClusterRoleBinding:
+// statement Subjects and Effect are not carried over, so the ClusterRole it
+// writes is bound to no subject.
+type rbacEnforcer struct {
+	client kubernetes.Interface
+}
+
+func newRBACEnforcer(config *rest.Config) (PolicyEnforcer, error) {
+	if config == nil {
+		return nil, fmt.Errorf("rbac authorization mode requires a rest.Config")
+	}
+
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kubernetes client for rbac enforcer: %v", err)
+	}
+
+	return &rbacEnforcer{client: client}, nil
+}
+
+func (r *rbacEnforcer) LoadPolicy(policy *v1.Policy) (string, error) {
+	clusterRole := statementsToClusterRole(policy.Name, policy.Spec.Statement)
+
+	existing, err := r.client.RbacV1().ClusterRoles().Get(clusterRole.Name, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		existing.Rules = clusterRole.Rules
+		if _, err := r.client.RbacV1().ClusterRoles().Update(existing); err != nil {
+			return "", fmt.Errorf("failed to update ClusterRole for policy %s: %v", policy.Name, err)
+		}
+	default:
+		if _, err := r.client.RbacV1().ClusterRoles().Create(clusterRole); err != nil {
+			return "", fmt.Errorf("failed to create ClusterRole for policy %s: %v", policy.Name, err)
+		}
+	}
+
+	return digestRules(statementsToCasbinRules(policy.Name, policy.Spec.Statement)), nil
+}
+
+func (r *rbacEnforcer) RemovePolicy(name string) error {
+	err := r.client.RbacV1().ClusterRoles().Delete(clusterRoleName(name), &metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete ClusterRole for policy %s: %v", name, err)
+	}
+	return nil
+}
+
+func clusterRoleName(policyName string) string {
+	return "tke:policy:" + policyName
+}
+
+// statementsToClusterRole translates a Policy's statements into ClusterRole
+// rules.
+//
+// CAUTION: Statement carries no API group of its own, and RBAC validation
+// rejects a rule with Resources set and APIGroups empty, so every rule is
+// granted across rbacv1.APIGroupAll ("*") rather than a specific group.
+// A statement scoped to e.g. "deployments" therefore also grants that verb
+// on any other group's "deployments" resource (extensions, apps, ...). This
+// is a real over-grant for the rbac backend; until Statement gains a Groups
+// field to scope rules properly, operators who can't accept that should use
+// the casbin backend instead (see AuthorizationOptions.Mode's flag help).
+func statementsToClusterRole(policyName string, statements []v1.Statement) *rbacv1.ClusterRole {
+	rules := make([]rbacv1.PolicyRule, 0, len(statements))
+	for _, statement := range statements {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{rbacv1.APIGroupAll},
+			Verbs:     statement.Actions,
+			Resources: statement.Resources,
+		})
+	}
+
+	return &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterRoleName(policyName),
+			Labels: map[string]string{
+				"auth.tkestack.io/policy": policyName,
+			},
+		},
+		Rules: rules,
+	}
+}
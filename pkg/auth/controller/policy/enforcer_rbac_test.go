@@ -0,0 +1,67 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package policy
+
+import (
+	"reflect"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	v1 "tkestack.io/tke/api/auth/v1"
+)
+
+func TestStatementsToClusterRoleSetsAPIGroups(t *testing.T) {
+	statements := []v1.Statement{
+		{Actions: []string{"get", "list"}, Resources: []string{"pods"}},
+		{Actions: []string{"delete"}, Resources: []string{"deployments"}},
+	}
+
+	clusterRole := statementsToClusterRole("policy-a", statements)
+
+	if clusterRole.Name != clusterRoleName("policy-a") {
+		t.Fatalf("ClusterRole.Name = %q, want %q", clusterRole.Name, clusterRoleName("policy-a"))
+	}
+	if clusterRole.Labels["auth.tkestack.io/policy"] != "policy-a" {
+		t.Fatalf("ClusterRole missing policy label, got %#v", clusterRole.Labels)
+	}
+
+	if len(clusterRole.Rules) != len(statements) {
+		t.Fatalf("got %d rules, want %d", len(clusterRole.Rules), len(statements))
+	}
+
+	for i, rule := range clusterRole.Rules {
+		if !reflect.DeepEqual(rule.APIGroups, []string{rbacv1.APIGroupAll}) {
+			t.Fatalf("rule %d APIGroups = %#v, want [%q] (omitting APIGroups with Resources set is rejected by RBAC validation)", i, rule.APIGroups, rbacv1.APIGroupAll)
+		}
+		if !reflect.DeepEqual(rule.Verbs, statements[i].Actions) {
+			t.Fatalf("rule %d Verbs = %#v, want %#v", i, rule.Verbs, statements[i].Actions)
+		}
+		if !reflect.DeepEqual(rule.Resources, statements[i].Resources) {
+			t.Fatalf("rule %d Resources = %#v, want %#v", i, rule.Resources, statements[i].Resources)
+		}
+	}
+}
+
+func TestStatementsToClusterRoleEmpty(t *testing.T) {
+	clusterRole := statementsToClusterRole("policy-a", nil)
+	if len(clusterRole.Rules) != 0 {
+		t.Fatalf("expected no rules for a policy with no statements, got %#v", clusterRole.Rules)
+	}
+}
@@ -0,0 +1,83 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package policy
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/rest"
+)
+
+// AuthorizationOptions holds the configuration needed to build the
+// PolicyEnforcer used by the policy controller.
+type AuthorizationOptions struct {
+	// Mode selects which PolicyEnforcer backend is constructed. One of
+	// casbin or rbac. webhook is intentionally not a valid value yet:
+	// the enforcer that selected it never actually called out to the
+	// configured webhook, so every Policy silently reported Active while
+	// enforcing nothing.
+	Mode string
+	// CasbinModelFile is the path to the casbin model definition used
+	// when Mode is casbin.
+	CasbinModelFile string
+	// RESTConfig is the Kubernetes client configuration used by the rbac
+	// backend to create ClusterRole objects. It is populated by the
+	// caller rather than by AddFlags.
+	RESTConfig *rest.Config
+}
+
+// NewAuthorizationOptions returns options defaulted to the casbin backend,
+// matching the behavior the controller had before this flag existed.
+func NewAuthorizationOptions() *AuthorizationOptions {
+	return &AuthorizationOptions{
+		Mode: string(AuthorizationModeCasbin),
+	}
+}
+
+// AddFlags adds the authorization flags to the specified FlagSet.
+func (o *AuthorizationOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Mode, "authorization-mode", o.Mode,
+		"The authorization backend used to enforce Policy objects. One of casbin, rbac. "+
+			"WARNING: rbac grants every statement's verbs across all API groups (Statement has "+
+			"no group of its own to scope ClusterRole rules to), so a statement naming a resource "+
+			"that also exists in another group grants access there too; prefer casbin unless "+
+			"that's acceptable for your cluster.")
+	fs.StringVar(&o.CasbinModelFile, "authorization-casbin-model-file", o.CasbinModelFile,
+		"Path to the casbin model file. Only used when authorization-mode is casbin.")
+}
+
+// Validate checks the options are internally consistent, returning all
+// errors found rather than failing on the first one.
+func (o *AuthorizationOptions) Validate() []error {
+	var errs []error
+
+	switch authorizationMode(o.Mode) {
+	case AuthorizationModeCasbin:
+		if o.CasbinModelFile == "" {
+			errs = append(errs, fmt.Errorf("authorization-casbin-model-file must be set when authorization-mode is casbin"))
+		}
+	case AuthorizationModeRBAC:
+		// RESTConfig is wired up by the caller, nothing to validate here.
+	default:
+		errs = append(errs, fmt.Errorf("invalid authorization-mode %q, must be one of casbin, rbac", o.Mode))
+	}
+
+	return errs
+}
@@ -0,0 +1,71 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package policy
+
+import "testing"
+
+func TestAuthorizationOptionsValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		opts    *AuthorizationOptions
+		wantErr bool
+	}{
+		{
+			name:    "casbin requires a model file",
+			opts:    &AuthorizationOptions{Mode: string(AuthorizationModeCasbin)},
+			wantErr: true,
+		},
+		{
+			name:    "casbin with model file is valid",
+			opts:    &AuthorizationOptions{Mode: string(AuthorizationModeCasbin), CasbinModelFile: "/tmp/model.conf"},
+			wantErr: false,
+		},
+		{
+			name:    "rbac needs no extra flags",
+			opts:    &AuthorizationOptions{Mode: string(AuthorizationModeRBAC)},
+			wantErr: false,
+		},
+		{
+			name:    "webhook is not a valid mode",
+			opts:    &AuthorizationOptions{Mode: "webhook"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown mode is rejected",
+			opts:    &AuthorizationOptions{Mode: "bogus"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := tc.opts.Validate()
+			if (len(errs) > 0) != tc.wantErr {
+				t.Fatalf("Validate() errs = %v, wantErr %v", errs, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewPolicyEnforcerRejectsWebhook(t *testing.T) {
+	_, err := newPolicyEnforcer(&AuthorizationOptions{Mode: "webhook"})
+	if err == nil {
+		t.Fatal("expected an error building a webhook enforcer, got nil")
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package policy
+
+import "sync"
+
+// cachedPolicy keeps the last successfully applied state of a single
+// v1.Policy so syncItem can skip the enforcer and the status update for a
+// generation it has already loaded.
+type cachedPolicy struct {
+	// digest is the rule digest returned by the enforcer the last time
+	// this policy's statements were loaded successfully.
+	digest string
+	// observedGeneration is the policy generation the digest above was
+	// computed from.
+	observedGeneration int64
+}
+
+// policyCache is a thread-safe store of cachedPolicy keyed by policy name.
+type policyCache struct {
+	mu        sync.RWMutex
+	policyMap map[string]*cachedPolicy
+}
+
+func (c *policyCache) get(name string) (*cachedPolicy, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	policy, ok := c.policyMap[name]
+	return policy, ok
+}
+
+func (c *policyCache) set(name string, policy *cachedPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.policyMap[name] = policy
+}
+
+func (c *policyCache) delete(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.policyMap, name)
+}
@@ -19,9 +19,12 @@
 package policy
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"sync"
 	"time"
+
 	v1 "tkestack.io/tke/api/auth/v1"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -33,6 +36,7 @@ import (
 	clientset "tkestack.io/tke/api/client/clientset/versioned"
 	authv1informer "tkestack.io/tke/api/client/informers/externalversions/auth/v1"
 	authv1lister "tkestack.io/tke/api/client/listers/auth/v1"
+	controllermetrics "tkestack.io/tke/pkg/auth/controller/metrics"
 	"tkestack.io/tke/pkg/business/controller/project/deletion"
 	controllerutil "tkestack.io/tke/pkg/controller"
 	"tkestack.io/tke/pkg/util/log"
@@ -49,26 +53,48 @@ const (
 	policyDeletionGracePeriod = 5 * time.Second
 
 	controllerName = "policy-controller"
+
+	// policyFinalizer is put on every Policy object so the controller
+	// can clean up the rules it loaded into the enforcer before the
+	// object is actually removed from etcd.
+	policyFinalizer = "policy.finalizer.auth.tkestack.io"
 )
 
-// Controller is responsible for performing actions dependent upon a project phase.
+// Controller is responsible for translating Policy objects into rules
+// loaded into a pluggable PolicyEnforcer backend, and keeping Policy.Status
+// up to date with the result.
 type Controller struct {
 	client       clientset.Interface
 	cache        *policyCache
 	queue        workqueue.RateLimitingInterface
 	lister       authv1lister.PolicyLister
 	listerSynced cache.InformerSynced
-	// helper to delete all resources in the project when the project is deleted.
+	// enforcer is the authorization backend rules are translated into.
+	enforcer PolicyEnforcer
+	// helper to delete all resources the project controller set up for
+	// a policy's associated project when the policy is deleted.
 	projectedResourcesDeleter deletion.ProjectedResourcesDeleterInterface
+
+	syncMu   sync.RWMutex
+	lastSync time.Time
 }
 
-// NewController creates a new Project object.
-func NewController(client clientset.Interface, policyInformer authv1informer.APIKeyInformer, resyncPeriod time.Duration) *Controller {
+// NewController creates a new Controller, building the PolicyEnforcer
+// selected by opts so callers don't need to know which authorization
+// backend is in effect.
+func NewController(client clientset.Interface, policyInformer authv1informer.PolicyInformer, resyncPeriod time.Duration, opts *AuthorizationOptions) (*Controller, error) {
+	enforcer, err := newPolicyEnforcer(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create policy enforcer: %v", err)
+	}
+
 	// create the controller so we can inject the enqueue function
 	controller := &Controller{
 		client:                    client,
 		cache:                     &policyCache{policyMap: make(map[string]*cachedPolicy)},
 		queue:                     workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName),
+		enforcer:                  enforcer,
+		projectedResourcesDeleter: deletion.NewProjectedResourcesDeleter(client),
 	}
 
 	if client != nil && client.AuthV1().RESTClient().GetRateLimiter() != nil {
@@ -77,7 +103,7 @@ func NewController(client clientset.Interface, policyInformer authv1informer.API
 
 	policyInformer.Informer().AddEventHandlerWithResyncPeriod(
 		cache.ResourceEventHandlerFuncs{
-			//AddFunc: controller.enqueue,
+			AddFunc: controller.enqueue,
 			UpdateFunc: func(oldObj, newObj interface{}) {
 				old, ok1 := oldObj.(*v1.Policy)
 				cur, ok2 := newObj.(*v1.Policy)
@@ -92,10 +118,10 @@ func NewController(client clientset.Interface, policyInformer authv1informer.API
 	)
 	controller.lister = policyInformer.Lister()
 	controller.listerSynced = policyInformer.Informer().HasSynced
-	return controller
+	return controller, nil
 }
 
-// obj could be an *v1.Project, or a DeletionFinalStateUnknown marker item.
+// obj could be an *v1.Policy, or a DeletionFinalStateUnknown marker item.
 func (c *Controller) enqueue(obj interface{}) {
 	key, err := controllerutil.KeyFunc(obj)
 	if err != nil {
@@ -105,6 +131,15 @@ func (c *Controller) enqueue(obj interface{}) {
 	c.queue.AddAfter(key, policyDeletionGracePeriod)
 }
 
+// Healthy reports whether the policy informer has synced and when this
+// controller last completed a syncItem call without error, satisfying
+// healthz.Reporter.
+func (c *Controller) Healthy() (synced bool, lastSync time.Time) {
+	c.syncMu.RLock()
+	defer c.syncMu.RUnlock()
+	return c.listerSynced(), c.lastSync
+}
+
 func (c *Controller) needsUpdate(old *v1.Policy, new *v1.Policy) bool {
 	if old.UID != new.UID {
 		return true
@@ -114,36 +149,48 @@ func (c *Controller) needsUpdate(old *v1.Policy, new *v1.Policy) bool {
 		return true
 	}
 
+	if !reflect.DeepEqual(old.DeletionTimestamp, new.DeletionTimestamp) {
+		return true
+	}
+
 	return false
 }
 
-
 // Run will set up the event handlers for types we are interested in, as well
-// as syncing informer caches and starting workers.
+// as syncing informer caches and starting workers. The context passed to
+// every worker is cancelled as soon as stopCh closes, so in-flight API
+// calls are aborted instead of outliving the controller.
 func (c *Controller) Run(workers int, stopCh <-chan struct{}) {
 	defer runtime.HandleCrash()
 	defer c.queue.ShutDown()
 
 	// Start the informer factories to begin populating the informer caches
-	log.Info("Starting project controller")
-	defer log.Info("Shutting down project controller")
+	log.Info("Starting policy controller")
+	defer log.Info("Shutting down policy controller")
 
 	if ok := cache.WaitForCacheSync(stopCh, c.listerSynced); !ok {
-		log.Error("Failed to wait for project caches to sync")
+		log.Error("Failed to wait for policy caches to sync")
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+
 	for i := 0; i < workers; i++ {
-		go wait.Until(c.worker, time.Second, stopCh)
+		go wait.Until(func() { c.worker(ctx) }, time.Second, stopCh)
 	}
 
 	<-stopCh
 }
 
-// worker processes the queue of project objects.
-// Each project can be in the queue at most once.
+// worker processes the queue of policy objects.
+// Each policy can be in the queue at most once.
 // The system ensures that no two workers can process
-// the same project at the same time.
-func (c *Controller) worker() {
+// the same policy at the same time.
+func (c *Controller) worker(ctx context.Context) {
 	workFunc := func() bool {
 		key, quit := c.queue.Get()
 		if quit {
@@ -151,14 +198,23 @@ func (c *Controller) worker() {
 		}
 		defer c.queue.Done(key)
 
-		requeue, err := c.syncItem(key.(string))
+		controllermetrics.SetQueueDepth(controllerName, c.queue.Len())
+
+		startTime := time.Now()
+		requeue, err := c.syncItem(ctx, key.(string))
+		controllermetrics.ObserveSync(controllerName, startTime, requeue, err)
+
 		if err == nil && !requeue {
+			c.syncMu.Lock()
+			c.lastSync = time.Now()
+			c.syncMu.Unlock()
+
 			// no error, forget this entry and return
 			c.queue.Forget(key)
 			return false
 		}
 
-		// rather than wait for a full resync, re-add the project to the queue to be processed
+		// rather than wait for a full resync, re-add the policy to the queue to be processed
 		c.queue.AddRateLimited(key)
 		runtime.HandleError(err)
 		return false
@@ -173,13 +229,15 @@ func (c *Controller) worker() {
 	}
 }
 
-// syncItem will sync the ApiKey with the given key if it has had
-// its expectations fulfilled, meaning the apikey has been deleted by user but not expired.
-func (c *Controller) syncItem(key string) (bool, error) {
+// syncItem loads the named policy's statements into the configured
+// PolicyEnforcer and reconciles Status to match. It returns requeue=true
+// when the failure is expected to be transient (enforcer or API errors)
+// so the caller re-adds the key with backoff instead of dropping it.
+func (c *Controller) syncItem(ctx context.Context, key string) (requeue bool, err error) {
 	startTime := time.Now()
 
 	defer func() {
-		log.Info("Finished syncing policy", log.String("apikey", key), log.Duration("processTime", time.Since(startTime)))
+		log.Info("Finished syncing policy", log.String("policy", key), log.Duration("processTime", time.Since(startTime)))
 	}()
 
 	_, name, err := cache.SplitMetaNamespaceKey(key)
@@ -190,13 +248,124 @@ func (c *Controller) syncItem(key string) (bool, error) {
 	policy, err := c.lister.Get(name)
 	switch {
 	case errors.IsNotFound(err):
-		log.Infof("Api key has been deleted %v", key)
+		log.Infof("Policy has been deleted %v", key)
+		c.cache.delete(name)
 		return false, nil
 	case err != nil:
 		log.Warn("Unable to retrieve policy from store", log.String("policy name", key), log.Err(err))
-	default:
-		// api key has been deleted check whether it has expired
-		log.Info("Create policy", log.Any("policy", policy))
+		return true, err
+	}
+
+	if policy.DeletionTimestamp != nil {
+		return c.processDeletion(ctx, policy)
+	}
+
+	return c.processUpdate(ctx, policy)
+}
+
+// processUpdate loads the policy's statements into the enforcer, retrying
+// (via requeue=true) on enforcer errors, which are assumed to be transient
+// (e.g. a lock held by another worker or a momentary backend outage).
+func (c *Controller) processUpdate(ctx context.Context, policy *v1.Policy) (bool, error) {
+	policy, err := c.ensureFinalizer(ctx, policy)
+	if err != nil {
+		return true, err
+	}
+
+	if cached, ok := c.cache.get(policy.Name); ok && cached.observedGeneration == policy.Generation {
+		return false, nil
+	}
+
+	digest, loadErr := c.enforcer.LoadPolicy(policy)
+	if loadErr != nil {
+		c.updateStatus(ctx, policy, "", loadErr)
+		return true, loadErr
+	}
+
+	c.cache.set(policy.Name, &cachedPolicy{digest: digest, observedGeneration: policy.Generation})
+	c.updateStatus(ctx, policy, digest, nil)
+	return false, nil
+}
+
+// processDeletion removes the policy's rules from the enforcer and the
+// resources it projected into member clusters, then clears the finalizer
+// so the apiserver can finish deleting the object.
+func (c *Controller) processDeletion(ctx context.Context, policy *v1.Policy) (bool, error) {
+	if !hasFinalizer(policy, policyFinalizer) {
+		return false, nil
 	}
+
+	if err := c.enforcer.RemovePolicy(policy.Name); err != nil {
+		return true, fmt.Errorf("failed to remove rules for policy %s from enforcer: %v", policy.Name, err)
+	}
+
+	if c.projectedResourcesDeleter != nil {
+		if err := c.projectedResourcesDeleter.Delete(policy.Name); err != nil {
+			return true, fmt.Errorf("failed to delete projected resources for policy %s: %v", policy.Name, err)
+		}
+	}
+
+	c.cache.delete(policy.Name)
+
+	updated := policy.DeepCopy()
+	updated.Finalizers = removeFinalizer(updated.Finalizers, policyFinalizer)
+	if _, err := c.client.AuthV1().Policies().Update(ctx, updated); err != nil {
+		return true, fmt.Errorf("failed to remove finalizer from policy %s: %v", policy.Name, err)
+	}
+
 	return false, nil
-}
\ No newline at end of file
+}
+
+func (c *Controller) ensureFinalizer(ctx context.Context, policy *v1.Policy) (*v1.Policy, error) {
+	if hasFinalizer(policy, policyFinalizer) {
+		return policy, nil
+	}
+
+	updated := policy.DeepCopy()
+	updated.Finalizers = append(updated.Finalizers, policyFinalizer)
+	result, err := c.client.AuthV1().Policies().Update(ctx, updated)
+	if err != nil {
+		return policy, fmt.Errorf("failed to add finalizer to policy %s: %v", policy.Name, err)
+	}
+	return result, nil
+}
+
+// updateStatus records the outcome of the last translation attempt on
+// Status. A failure to write Status is logged rather than returned; whether
+// the key is requeued is up to the caller, and processUpdate requeues on
+// every LoadPolicy error.
+func (c *Controller) updateStatus(ctx context.Context, policy *v1.Policy, digest string, translationErr error) {
+	updated := policy.DeepCopy()
+	updated.Status.ObservedGeneration = policy.Generation
+	if translationErr != nil {
+		updated.Status.Phase = v1.PolicyFailed
+		updated.Status.Reason = translationErr.Error()
+	} else {
+		updated.Status.Phase = v1.PolicyActive
+		updated.Status.Reason = ""
+		updated.Status.RuleDigest = digest
+	}
+
+	if _, err := c.client.AuthV1().Policies().UpdateStatus(ctx, updated); err != nil {
+		log.Error("Failed to update policy status", log.String("policy", policy.Name), log.Err(err))
+	}
+}
+
+func hasFinalizer(policy *v1.Policy, finalizer string) bool {
+	for _, f := range policy.Finalizers {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+func removeFinalizer(finalizers []string, finalizer string) []string {
+	result := make([]string, 0, len(finalizers))
+	for _, f := range finalizers {
+		if f != finalizer {
+			result = append(result, f)
+		}
+	}
+	return result
+}
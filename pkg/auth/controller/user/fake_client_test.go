@@ -0,0 +1,151 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package user
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+
+	v1 "tkestack.io/tke/api/auth/v1"
+	clientset "tkestack.io/tke/api/client/clientset/versioned"
+	authv1client "tkestack.io/tke/api/client/clientset/versioned/typed/auth/v1"
+)
+
+// fakeClientset is a minimal, in-memory clientset.Interface covering only
+// what user_bindings.go and user_controller.go actually call. Embedding the
+// real interfaces (left nil) means any method this test doesn't need
+// panics on use instead of silently compiling away a gap in the fake.
+type fakeClientset struct {
+	policies *fakePolicyClient
+	roles    *fakeRoleClient
+	users    *fakeUserClient
+}
+
+func newFakeClientset() *fakeClientset {
+	return &fakeClientset{
+		policies: &fakePolicyClient{byName: map[string]*v1.Policy{}},
+		roles:    &fakeRoleClient{byName: map[string]*v1.Role{}},
+		users:    &fakeUserClient{byName: map[string]*v1.User{}},
+	}
+}
+
+func (f *fakeClientset) AuthV1() authv1client.AuthV1Interface { return f }
+func (f *fakeClientset) RESTClient() rest.Interface {
+	return nil
+}
+func (f *fakeClientset) Policies() authv1client.PolicyInterface { return f.policies }
+func (f *fakeClientset) Roles() authv1client.RoleInterface      { return f.roles }
+func (f *fakeClientset) Users() authv1client.UserInterface      { return f.users }
+
+var _ clientset.Interface = (*fakeClientset)(nil)
+
+func notFoundErr(resource, name string) error {
+	return errors.NewNotFound(schema.GroupResource{Group: "auth.tkestack.io", Resource: resource}, name)
+}
+
+func matchesSelector(labels map[string]string, selector string) bool {
+	if selector == "" {
+		return true
+	}
+	parts := strings.SplitN(selector, "=", 2)
+	if len(parts) != 2 {
+		return false
+	}
+	return labels[parts[0]] == parts[1]
+}
+
+// fakePolicyClient
+type fakePolicyClient struct {
+	authv1client.PolicyInterface
+	byName map[string]*v1.Policy
+}
+
+func (f *fakePolicyClient) Get(_ context.Context, name string, _ metav1.GetOptions) (*v1.Policy, error) {
+	policy, ok := f.byName[name]
+	if !ok {
+		return nil, notFoundErr("policies", name)
+	}
+	return policy.DeepCopy(), nil
+}
+
+func (f *fakePolicyClient) Update(_ context.Context, policy *v1.Policy) (*v1.Policy, error) {
+	f.byName[policy.Name] = policy.DeepCopy()
+	return policy.DeepCopy(), nil
+}
+
+func (f *fakePolicyClient) List(_ context.Context, opts metav1.ListOptions) (*v1.PolicyList, error) {
+	list := &v1.PolicyList{}
+	for _, policy := range f.byName {
+		if matchesSelector(policy.Labels, opts.LabelSelector) {
+			list.Items = append(list.Items, *policy.DeepCopy())
+		}
+	}
+	return list, nil
+}
+
+// fakeRoleClient
+type fakeRoleClient struct {
+	authv1client.RoleInterface
+	byName map[string]*v1.Role
+}
+
+func (f *fakeRoleClient) Get(_ context.Context, name string, _ metav1.GetOptions) (*v1.Role, error) {
+	role, ok := f.byName[name]
+	if !ok {
+		return nil, notFoundErr("roles", name)
+	}
+	return role.DeepCopy(), nil
+}
+
+func (f *fakeRoleClient) Update(_ context.Context, role *v1.Role) (*v1.Role, error) {
+	f.byName[role.Name] = role.DeepCopy()
+	return role.DeepCopy(), nil
+}
+
+func (f *fakeRoleClient) List(_ context.Context, opts metav1.ListOptions) (*v1.RoleList, error) {
+	list := &v1.RoleList{}
+	for _, role := range f.byName {
+		if matchesSelector(role.Labels, opts.LabelSelector) {
+			list.Items = append(list.Items, *role.DeepCopy())
+		}
+	}
+	return list, nil
+}
+
+// fakeUserClient. UserInterface isn't context-aware yet (see
+// versioned/typed/auth/v1/user.go), so these methods take no ctx.
+type fakeUserClient struct {
+	authv1client.UserInterface
+	byName map[string]*v1.User
+}
+
+func (f *fakeUserClient) Update(user *v1.User) (*v1.User, error) {
+	f.byName[user.Name] = user.DeepCopy()
+	return user.DeepCopy(), nil
+}
+
+func (f *fakeUserClient) UpdateStatus(user *v1.User) (*v1.User, error) {
+	f.byName[user.Name] = user.DeepCopy()
+	return user.DeepCopy(), nil
+}
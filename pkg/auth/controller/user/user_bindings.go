@@ -0,0 +1,216 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package user
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "tkestack.io/tke/api/auth/v1"
+)
+
+// userBindingLabel marks every Role/Policy binding this controller created
+// for a given user, so cleanupRoleBindings can find them again on deletion
+// without having to remember what it created in a separate store.
+const userBindingLabel = "auth.tkestack.io/bound-user"
+
+// reconcileRoleBindings grants the user the roles and policies implied by
+// Spec.Policies/Spec.Roles, creating any binding that doesn't already
+// exist. It is additive only: bindings the user no longer references are
+// left to whoever created them, since ownership isn't tracked per-field.
+func (c *Controller) reconcileRoleBindings(user *v1.User) error {
+	for _, policyName := range user.Spec.Policies {
+		if err := c.bindPolicy(user, policyName); err != nil {
+			return err
+		}
+	}
+
+	for _, roleName := range user.Spec.Roles {
+		if err := c.bindRole(user, roleName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bindPolicy adds user to every statement's Subjects on the named Policy
+// and labels the Policy with userBindingLabel, so cleanupRoleBindings can
+// find it again on deletion. It is a no-op if the user is already bound.
+func (c *Controller) bindPolicy(user *v1.User, policyName string) error {
+	policy, err := c.client.AuthV1().Policies().Get(context.TODO(), policyName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to look up policy %s for user %s: %v", policyName, user.Name, err)
+	}
+
+	updated := policy.DeepCopy()
+	changed := false
+	for i := range updated.Spec.Statement {
+		if !containsString(updated.Spec.Statement[i].Subjects, user.Name) {
+			updated.Spec.Statement[i].Subjects = append(updated.Spec.Statement[i].Subjects, user.Name)
+			changed = true
+		}
+	}
+	if updated.Labels == nil {
+		updated.Labels = make(map[string]string, 1)
+	}
+	if updated.Labels[userBindingLabel] != user.Name {
+		updated.Labels[userBindingLabel] = user.Name
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+
+	if _, err := c.client.AuthV1().Policies().Update(context.TODO(), updated); err != nil {
+		return fmt.Errorf("failed to bind policy %s to user %s: %v", policyName, user.Name, err)
+	}
+	return nil
+}
+
+// bindRole labels the named Role with userBindingLabel so cleanupRoleBindings
+// can find it again on deletion. Role, unlike Policy, carries no subject
+// list of its own, so the label is the only record of the binding.
+func (c *Controller) bindRole(user *v1.User, roleName string) error {
+	role, err := c.client.AuthV1().Roles().Get(context.TODO(), roleName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to look up role %s for user %s: %v", roleName, user.Name, err)
+	}
+
+	if role.Labels[userBindingLabel] == user.Name {
+		return nil
+	}
+
+	updated := role.DeepCopy()
+	if updated.Labels == nil {
+		updated.Labels = make(map[string]string, 1)
+	}
+	updated.Labels[userBindingLabel] = user.Name
+
+	if _, err := c.client.AuthV1().Roles().Update(context.TODO(), updated); err != nil {
+		return fmt.Errorf("failed to bind role %s to user %s: %v", roleName, user.Name, err)
+	}
+	return nil
+}
+
+// reconcileNamespaceMembership materializes the namespaces a user's tenants
+// imply onto Status.Namespaces, so namespace-scoped authorization checks
+// can resolve membership without cross-referencing the tenant object on
+// every request.
+func (c *Controller) reconcileNamespaceMembership(user *v1.User) error {
+	namespaces := make([]string, 0, len(user.Spec.TenantIDs))
+	for _, tenantID := range user.Spec.TenantIDs {
+		namespaces = append(namespaces, tenantNamespace(tenantID))
+	}
+	sort.Strings(namespaces)
+
+	if stringSlicesEqual(user.Status.Namespaces, namespaces) {
+		return nil
+	}
+
+	updated := user.DeepCopy()
+	updated.Status.Namespaces = namespaces
+	if _, err := c.client.AuthV1().Users().UpdateStatus(updated); err != nil {
+		return fmt.Errorf("failed to update namespace membership for user %s: %v", user.Name, err)
+	}
+	return nil
+}
+
+// tenantNamespace returns the namespace a tenant's resources live in.
+func tenantNamespace(tenantID string) string {
+	return "tenant-" + tenantID
+}
+
+// cleanupRoleBindings removes every binding reconcileRoleBindings may have
+// created for the user, identified by userBindingLabel rather than the
+// user's current Spec so bindings for policies/roles the user has since
+// lost are still cleaned up.
+func (c *Controller) cleanupRoleBindings(user *v1.User) error {
+	policies, err := c.client.AuthV1().Policies().List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", userBindingLabel, user.Name),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list policy bindings for user %s: %v", user.Name, err)
+	}
+
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		updated := policy.DeepCopy()
+		for j := range updated.Spec.Statement {
+			updated.Spec.Statement[j].Subjects = removeString(updated.Spec.Statement[j].Subjects, user.Name)
+		}
+		delete(updated.Labels, userBindingLabel)
+		if _, err := c.client.AuthV1().Policies().Update(context.TODO(), updated); err != nil {
+			return fmt.Errorf("failed to unbind policy %s from user %s: %v", policy.Name, user.Name, err)
+		}
+	}
+
+	roles, err := c.client.AuthV1().Roles().List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", userBindingLabel, user.Name),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list role bindings for user %s: %v", user.Name, err)
+	}
+
+	for i := range roles.Items {
+		role := &roles.Items[i]
+		updated := role.DeepCopy()
+		delete(updated.Labels, userBindingLabel)
+		if _, err := c.client.AuthV1().Roles().Update(context.TODO(), updated); err != nil {
+			return fmt.Errorf("failed to unbind role %s from user %s: %v", role.Name, user.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(values []string, target string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != target {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,179 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package user
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "tkestack.io/tke/api/auth/v1"
+)
+
+func newTestController(fc *fakeClientset) *Controller {
+	return &Controller{client: fc}
+}
+
+func TestBindPolicyAddsSubjectAndLabel(t *testing.T) {
+	fc := newFakeClientset()
+	fc.policies.byName["policy-a"] = &v1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy-a"},
+		Spec: v1.PolicySpec{
+			Statement: []v1.Statement{{Subjects: []string{"existing"}}},
+		},
+	}
+	c := newTestController(fc)
+	u := &v1.User{ObjectMeta: metav1.ObjectMeta{Name: "alice"}}
+
+	if err := c.bindPolicy(u, "policy-a"); err != nil {
+		t.Fatalf("bindPolicy() error = %v", err)
+	}
+
+	got := fc.policies.byName["policy-a"]
+	want := []string{"existing", "alice"}
+	if !reflect.DeepEqual(got.Spec.Statement[0].Subjects, want) {
+		t.Fatalf("Subjects = %#v, want %#v", got.Spec.Statement[0].Subjects, want)
+	}
+	if got.Labels[userBindingLabel] != "alice" {
+		t.Fatalf("Labels[%s] = %q, want %q", userBindingLabel, got.Labels[userBindingLabel], "alice")
+	}
+}
+
+func TestBindPolicyIsNoopWhenAlreadyBound(t *testing.T) {
+	fc := newFakeClientset()
+	policy := &v1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy-a", Labels: map[string]string{userBindingLabel: "alice"}},
+		Spec: v1.PolicySpec{
+			Statement: []v1.Statement{{Subjects: []string{"alice"}}},
+		},
+	}
+	fc.policies.byName["policy-a"] = policy
+
+	c := newTestController(fc)
+	u := &v1.User{ObjectMeta: metav1.ObjectMeta{Name: "alice"}}
+
+	if err := c.bindPolicy(u, "policy-a"); err != nil {
+		t.Fatalf("bindPolicy() error = %v", err)
+	}
+
+	got := fc.policies.byName["policy-a"]
+	if !reflect.DeepEqual(got.Spec.Statement[0].Subjects, []string{"alice"}) {
+		t.Fatalf("Subjects changed on a no-op bind: %#v", got.Spec.Statement[0].Subjects)
+	}
+}
+
+func TestBindRoleSetsLabel(t *testing.T) {
+	fc := newFakeClientset()
+	fc.roles.byName["role-a"] = &v1.Role{ObjectMeta: metav1.ObjectMeta{Name: "role-a"}}
+	c := newTestController(fc)
+	u := &v1.User{ObjectMeta: metav1.ObjectMeta{Name: "alice"}}
+
+	if err := c.bindRole(u, "role-a"); err != nil {
+		t.Fatalf("bindRole() error = %v", err)
+	}
+
+	if got := fc.roles.byName["role-a"].Labels[userBindingLabel]; got != "alice" {
+		t.Fatalf("Labels[%s] = %q, want %q", userBindingLabel, got, "alice")
+	}
+}
+
+func TestReconcileNamespaceMembershipSetsStatus(t *testing.T) {
+	fc := newFakeClientset()
+	c := newTestController(fc)
+	u := &v1.User{
+		ObjectMeta: metav1.ObjectMeta{Name: "alice"},
+		Spec:       v1.UserSpec{TenantIDs: []string{"b", "a"}},
+	}
+	fc.users.byName["alice"] = u
+
+	if err := c.reconcileNamespaceMembership(u); err != nil {
+		t.Fatalf("reconcileNamespaceMembership() error = %v", err)
+	}
+
+	want := []string{"tenant-a", "tenant-b"}
+	got := fc.users.byName["alice"].Status.Namespaces
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Status.Namespaces = %#v, want %#v (sorted)", got, want)
+	}
+}
+
+func TestReconcileNamespaceMembershipIsNoopWhenUnchanged(t *testing.T) {
+	fc := newFakeClientset()
+	c := newTestController(fc)
+	u := &v1.User{
+		ObjectMeta: metav1.ObjectMeta{Name: "alice"},
+		Spec:       v1.UserSpec{TenantIDs: []string{"a"}},
+		Status:     v1.UserStatus{Namespaces: []string{"tenant-a"}},
+	}
+
+	if err := c.reconcileNamespaceMembership(u); err != nil {
+		t.Fatalf("reconcileNamespaceMembership() error = %v", err)
+	}
+
+	if _, stored := fc.users.byName["alice"]; stored {
+		t.Fatalf("UpdateStatus was called despite no change")
+	}
+}
+
+func TestCleanupRoleBindingsRemovesSubjectAndLabel(t *testing.T) {
+	fc := newFakeClientset()
+	fc.policies.byName["policy-a"] = &v1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy-a", Labels: map[string]string{userBindingLabel: "alice"}},
+		Spec: v1.PolicySpec{
+			Statement: []v1.Statement{{Subjects: []string{"alice", "bob"}}},
+		},
+	}
+	fc.policies.byName["policy-untouched"] = &v1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy-untouched", Labels: map[string]string{userBindingLabel: "bob"}},
+		Spec: v1.PolicySpec{
+			Statement: []v1.Statement{{Subjects: []string{"bob"}}},
+		},
+	}
+	fc.roles.byName["role-a"] = &v1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "role-a", Labels: map[string]string{userBindingLabel: "alice"}},
+	}
+
+	c := newTestController(fc)
+	u := &v1.User{ObjectMeta: metav1.ObjectMeta{Name: "alice"}}
+
+	if err := c.cleanupRoleBindings(u); err != nil {
+		t.Fatalf("cleanupRoleBindings() error = %v", err)
+	}
+
+	boundPolicy := fc.policies.byName["policy-a"]
+	if containsString(boundPolicy.Spec.Statement[0].Subjects, "alice") {
+		t.Fatalf("alice still present in Subjects: %#v", boundPolicy.Spec.Statement[0].Subjects)
+	}
+	if !containsString(boundPolicy.Spec.Statement[0].Subjects, "bob") {
+		t.Fatalf("bob should not have been removed: %#v", boundPolicy.Spec.Statement[0].Subjects)
+	}
+	if _, ok := boundPolicy.Labels[userBindingLabel]; ok {
+		t.Fatalf("userBindingLabel should have been removed from policy-a")
+	}
+
+	untouched := fc.policies.byName["policy-untouched"]
+	if untouched.Labels[userBindingLabel] != "bob" {
+		t.Fatalf("policy-untouched should not have been modified, got labels %#v", untouched.Labels)
+	}
+
+	if _, ok := fc.roles.byName["role-a"].Labels[userBindingLabel]; ok {
+		t.Fatalf("userBindingLabel should have been removed from role-a")
+	}
+}
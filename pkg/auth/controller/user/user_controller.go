@@ -0,0 +1,311 @@
+/*
+ * Tencent is pleased to support the open source community by making TKEStack
+ * available.
+ *
+ * Copyright (C) 2012-2019 Tencent. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the “License”); you may not use
+ * this file except in compliance with the License. You may obtain a copy of the
+ * License at
+ *
+ * https://opensource.org/licenses/Apache-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an “AS IS” BASIS, WITHOUT
+ * WARRANTIES OF ANY KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package user reconciles iam/auth User objects: it grants the role and
+// policy bindings implied by a new user's attributes, keeps the user's
+// per-tenant namespace membership in sync, and tears those bindings down
+// again when the user is deleted.
+package user
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	v1 "tkestack.io/tke/api/auth/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	clientset "tkestack.io/tke/api/client/clientset/versioned"
+	authv1informer "tkestack.io/tke/api/client/informers/externalversions/auth/v1"
+	authv1lister "tkestack.io/tke/api/client/listers/auth/v1"
+	controllermetrics "tkestack.io/tke/pkg/auth/controller/metrics"
+	controllerutil "tkestack.io/tke/pkg/controller"
+	"tkestack.io/tke/pkg/util/log"
+	"tkestack.io/tke/pkg/util/metrics"
+)
+
+const (
+	// userDeletionGracePeriod mirrors policyDeletionGracePeriod: it gives
+	// HA apiservers and non-leader etcd members time to observe the
+	// delete before this controller starts tearing down bindings.
+	userDeletionGracePeriod = 5 * time.Second
+
+	controllerName = "user-controller"
+
+	// userFinalizer is put on every User object so the controller can
+	// clean up the role/policy bindings it created before the object is
+	// actually removed from etcd.
+	userFinalizer = "user.finalizer.auth.tkestack.io"
+)
+
+// Controller reconciles User objects with their derived role/policy
+// bindings and per-tenant namespace membership.
+type Controller struct {
+	client       clientset.Interface
+	queue        workqueue.RateLimitingInterface
+	lister       authv1lister.UserLister
+	listerSynced cache.InformerSynced
+
+	syncMu   sync.RWMutex
+	lastSync time.Time
+}
+
+// Healthy reports whether the user informer has synced and when this
+// controller last completed a syncItem call without error, satisfying
+// healthz.Reporter.
+func (c *Controller) Healthy() (synced bool, lastSync time.Time) {
+	c.syncMu.RLock()
+	defer c.syncMu.RUnlock()
+	return c.listerSynced(), c.lastSync
+}
+
+// NewController creates a new user Controller.
+func NewController(client clientset.Interface, userInformer authv1informer.UserInformer, resyncPeriod time.Duration) *Controller {
+	controller := &Controller{
+		client: client,
+		queue:  workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName),
+	}
+
+	if client != nil && client.AuthV1().RESTClient().GetRateLimiter() != nil {
+		_ = metrics.RegisterMetricAndTrackRateLimiterUsage("user_controller", client.AuthV1().RESTClient().GetRateLimiter())
+	}
+
+	userInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: controller.enqueue,
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				old, ok1 := oldObj.(*v1.User)
+				cur, ok2 := newObj.(*v1.User)
+				if ok1 && ok2 && controller.needsUpdate(old, cur) {
+					controller.enqueue(newObj)
+				}
+			},
+			DeleteFunc: controller.enqueue,
+		},
+		resyncPeriod,
+	)
+	controller.lister = userInformer.Lister()
+	controller.listerSynced = userInformer.Informer().HasSynced
+	return controller
+}
+
+// obj could be an *v1.User, or a DeletionFinalStateUnknown marker item.
+func (c *Controller) enqueue(obj interface{}) {
+	key, err := controllerutil.KeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(fmt.Errorf("couldn't get key for object %+v: %v", obj, err))
+		return
+	}
+	c.queue.AddAfter(key, userDeletionGracePeriod)
+}
+
+// needsUpdate only enqueues on the fields reconciliation actually depends
+// on, so routine resyncs of unrelated status fields don't add churn to
+// the queue.
+func (c *Controller) needsUpdate(old *v1.User, new *v1.User) bool {
+	if old.UID != new.UID {
+		return true
+	}
+
+	if !reflect.DeepEqual(old.Spec, new.Spec) {
+		return true
+	}
+
+	if !reflect.DeepEqual(old.Groups, new.Groups) {
+		return true
+	}
+
+	if !reflect.DeepEqual(old.Annotations, new.Annotations) {
+		return true
+	}
+
+	if !reflect.DeepEqual(old.DeletionTimestamp, new.DeletionTimestamp) {
+		return true
+	}
+
+	return false
+}
+
+// Run will set up the event handlers for types we are interested in, as well
+// as syncing informer caches and starting workers.
+func (c *Controller) Run(workers int, stopCh <-chan struct{}) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	log.Info("Starting user controller")
+	defer log.Info("Shutting down user controller")
+
+	if ok := cache.WaitForCacheSync(stopCh, c.listerSynced); !ok {
+		log.Error("Failed to wait for user caches to sync")
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(c.worker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+// worker processes the queue of user objects. Each user can be in the
+// queue at most once; no two workers process the same user concurrently.
+func (c *Controller) worker() {
+	workFunc := func() bool {
+		key, quit := c.queue.Get()
+		if quit {
+			return true
+		}
+		defer c.queue.Done(key)
+
+		controllermetrics.SetQueueDepth(controllerName, c.queue.Len())
+
+		startTime := time.Now()
+		requeue, err := c.syncItem(key.(string))
+		controllermetrics.ObserveSync(controllerName, startTime, requeue, err)
+
+		if err == nil && !requeue {
+			c.syncMu.Lock()
+			c.lastSync = time.Now()
+			c.syncMu.Unlock()
+
+			c.queue.Forget(key)
+			return false
+		}
+
+		c.queue.AddRateLimited(key)
+		runtime.HandleError(err)
+		return false
+	}
+
+	for {
+		quit := workFunc()
+		if quit {
+			return
+		}
+	}
+}
+
+// syncItem reconciles the named user's derived bindings and namespace
+// membership, or tears them down if the user is being deleted.
+func (c *Controller) syncItem(key string) (requeue bool, err error) {
+	startTime := time.Now()
+	defer func() {
+		log.Info("Finished syncing user", log.String("user", key), log.Duration("processTime", time.Since(startTime)))
+	}()
+
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return false, err
+	}
+
+	user, err := c.lister.Get(name)
+	switch {
+	case errors.IsNotFound(err):
+		log.Infof("User has been deleted %v", key)
+		return false, nil
+	case err != nil:
+		log.Warn("Unable to retrieve user from store", log.String("user name", key), log.Err(err))
+		return true, err
+	}
+
+	if user.DeletionTimestamp != nil {
+		return c.processDeletion(user)
+	}
+
+	return c.processUpdate(user)
+}
+
+// processUpdate grants the role/policy bindings and namespace membership
+// implied by the user's attributes, adding the finalizer first so a crash
+// partway through doesn't leave orphaned bindings behind.
+func (c *Controller) processUpdate(user *v1.User) (bool, error) {
+	user, err := c.ensureFinalizer(user)
+	if err != nil {
+		return true, err
+	}
+
+	if err := c.reconcileRoleBindings(user); err != nil {
+		return true, fmt.Errorf("failed to reconcile role bindings for user %s: %v", user.Name, err)
+	}
+
+	if err := c.reconcileNamespaceMembership(user); err != nil {
+		return true, fmt.Errorf("failed to reconcile namespace membership for user %s: %v", user.Name, err)
+	}
+
+	return false, nil
+}
+
+// processDeletion cleans up the role/policy bindings this controller
+// created, then clears the finalizer so the apiserver can finish deleting
+// the object. Namespace membership lives on the User's own Status and is
+// not touched here.
+func (c *Controller) processDeletion(user *v1.User) (bool, error) {
+	if !hasFinalizer(user, userFinalizer) {
+		return false, nil
+	}
+
+	if err := c.cleanupRoleBindings(user); err != nil {
+		return true, fmt.Errorf("failed to clean up role bindings for user %s: %v", user.Name, err)
+	}
+
+	updated := user.DeepCopy()
+	updated.Finalizers = removeFinalizer(updated.Finalizers, userFinalizer)
+	if _, err := c.client.AuthV1().Users().Update(updated); err != nil {
+		return true, fmt.Errorf("failed to remove finalizer from user %s: %v", user.Name, err)
+	}
+
+	return false, nil
+}
+
+func (c *Controller) ensureFinalizer(user *v1.User) (*v1.User, error) {
+	if hasFinalizer(user, userFinalizer) {
+		return user, nil
+	}
+
+	updated := user.DeepCopy()
+	updated.Finalizers = append(updated.Finalizers, userFinalizer)
+	result, err := c.client.AuthV1().Users().Update(updated)
+	if err != nil {
+		return user, fmt.Errorf("failed to add finalizer to user %s: %v", user.Name, err)
+	}
+	return result, nil
+}
+
+func hasFinalizer(user *v1.User, finalizer string) bool {
+	for _, f := range user.Finalizers {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+func removeFinalizer(finalizers []string, finalizer string) []string {
+	result := make([]string, 0, len(finalizers))
+	for _, f := range finalizers {
+		if f != finalizer {
+			result = append(result, f)
+		}
+	}
+	return result
+}